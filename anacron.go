@@ -0,0 +1,33 @@
+package gounix
+
+import "time"
+
+// AnacronJob anacron job manager. runs cron job daily, weekly, monthly
+// or yearly schedule via /etc/anacrontab, so runs missed while machine
+// was powered off executed on next start.
+type AnacronJob interface {
+	// Period overrides the period in days derived from the cron job schedule.
+	Period(days int) AnacronJob
+	// Delay sets the delay after anacron start before running the job (default 5 minutes).
+	Delay(d time.Duration) AnacronJob
+	// Compile compiles the job into anacrontab line
+	// (period, delay in minutes, job identifier and command).
+	Compile() (string, error)
+	// Exists checks if the job exists in anacrontab.
+	Exists() (bool, error)
+	// Install installs the job. existing job with the same identifier replaced.
+	Install() error
+	// Uninstall removes the job from anacrontab.
+	Uninstall() error
+}
+
+// NewAnacronJob creates anacron job of cron job with identifier.
+// period derived from cron job schedule: Daily is 1 day, Weekly 7 days,
+// Monthly @monthly and Yearly 365 days. time of day ignored by anacron.
+func NewAnacronJob(id string, job CronJob) AnacronJob {
+	anacron := new(anacronDriver)
+	anacron.id = id
+	anacron.job = job
+	anacron.delay = 5 * time.Minute
+	return anacron
+}
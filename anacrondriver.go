@@ -0,0 +1,185 @@
+package gounix
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// anacrontabPath anacron jobs table path.
+const anacrontabPath = "/etc/anacrontab"
+
+type anacronDriver struct {
+	id     string
+	job    CronJob
+	period int
+	delay  time.Duration
+}
+
+// periodOf derive anacron period from cron job schedule.
+func (a anacronDriver) periodOf(driver *cronDriver) (string, error) {
+	if a.period > 0 {
+		return strconv.Itoa(a.period), nil
+	} else if driver.reboot {
+		return "", errors.New("@reboot cron job has no anacron period")
+	}
+
+	schedule, err := newCronSchedule(strings.Join([]string{driver.minute, driver.hour, driver.day, driver.month, driver.weekday}, " "))
+	if err != nil {
+		return "", err
+	}
+	once := bitCount(schedule.minute) == 1 && bitCount(schedule.hour) == 1
+	day := dayField.full(schedule.day)
+	month := monthField.full(schedule.month)
+	weekday := weekdayField.full(schedule.weekday)
+	switch {
+	case once && day && month && weekday:
+		return "1", nil
+	case once && day && month && bitCount(schedule.weekday) == 1:
+		return "7", nil
+	case once && bitCount(schedule.day) == 1 && month && weekday:
+		return "@monthly", nil
+	case once && bitCount(schedule.day) == 1 && bitCount(schedule.month) == 1 && weekday:
+		return "365", nil
+	}
+	return "", fmt.Errorf("cron schedule %q has no anacron period", strings.Join([]string{driver.minute, driver.hour, driver.day, driver.month, driver.weekday}, " "))
+}
+
+func (a *anacronDriver) Period(days int) AnacronJob {
+	a.period = days
+	return a
+}
+
+func (a *anacronDriver) Delay(d time.Duration) AnacronJob {
+	a.delay = d
+	return a
+}
+
+func (a *anacronDriver) Compile() (string, error) {
+	driver, ok := a.job.(*cronDriver)
+	if !ok || driver == nil {
+		return "", errors.New("unsupported cron job implementation")
+	}
+
+	// Validate identifier and cron entry, identifier used as timestamp file name
+	// and anacron runs jobs as root
+	if a.id == "" || strings.ContainsFunc(a.id, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '/'
+	}) {
+		return "", fmt.Errorf("invalid anacron job identifier %q", a.id)
+	} else if a.period < 0 {
+		return "", fmt.Errorf("invalid anacron job period %d", a.period)
+	} else if a.delay < 0 {
+		return "", fmt.Errorf("invalid anacron job delay %s", a.delay)
+	} else if errs := driver.entryErrors(); len(errs) > 0 {
+		return "", errors.Join(errs...)
+	} else if driver.user != "" && driver.user != "root" {
+		return "", fmt.Errorf("anacron job runs as root, user %q not supported", driver.user)
+	}
+
+	period, err := a.periodOf(driver)
+	if err != nil {
+		return "", err
+	}
+	delay := strconv.Itoa(int(a.delay / time.Minute))
+	return period + "\t" + delay + "\t" + a.id + "\t" + driver.wrapped(), nil
+}
+
+// runner get command runner of cron job.
+func (a *anacronDriver) runner() Runner {
+	if driver, ok := a.job.(*cronDriver); ok && driver != nil {
+		return driver.runner
+	}
+	return nil
+}
+
+// read reads anacrontab lines.
+func (a *anacronDriver) read() ([]string, error) {
+	content, err := readFile(a.runner(), anacrontabPath)
+	if os.IsNotExist(err) || (err == nil && len(content) == 0) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"), nil
+}
+
+// find finds index of job lines in anacrontab lines.
+func (a *anacronDriver) find(lines []string) []int {
+	result := make([]int, 0)
+	for i, line := range lines {
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if fields, command := splitFields(text, 3); len(fields) == 3 && command != "" && fields[2] == a.id {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// write writes anacrontab lines.
+func (a *anacronDriver) write(lines []string) error {
+	content := strings.Join(lines, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return writeFile(a.runner(), anacrontabPath, []byte(content), 0644)
+}
+
+func (a *anacronDriver) Exists() (bool, error) {
+	lines, err := a.read()
+	if err != nil {
+		return false, err
+	}
+	return len(a.find(lines)) > 0, nil
+}
+
+func (a *anacronDriver) Install() error {
+	// Compile job
+	line, err := a.Compile()
+	if err != nil {
+		return err
+	}
+
+	// Read jobs
+	lines, err := a.read()
+	if err != nil {
+		return err
+	}
+
+	// Replace first job line and remove duplicates or append
+	indexes := a.find(lines)
+	if len(indexes) == 0 {
+		lines = append(lines, line)
+	} else {
+		lines[indexes[0]] = line
+		for n := len(indexes) - 1; n > 0; n-- {
+			lines = append(lines[:indexes[n]], lines[indexes[n]+1:]...)
+		}
+	}
+	return a.write(lines)
+}
+
+func (a *anacronDriver) Uninstall() error {
+	// Read jobs
+	lines, err := a.read()
+	if err != nil {
+		return err
+	}
+
+	// Remove job lines
+	indexes := a.find(lines)
+	if len(indexes) == 0 {
+		return nil
+	}
+	for n := len(indexes) - 1; n >= 0; n-- {
+		lines = append(lines[:indexes[n]], lines[indexes[n]+1:]...)
+	}
+	return a.write(lines)
+}
@@ -0,0 +1,90 @@
+package gounix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AtJob represents one-shot job submitted to at(1).
+type AtJob interface {
+	// At sets run time of the job. date and clock of t interpreted in
+	// job timezone (nil timezone keeps t instant).
+	At(t time.Time) AtJob
+	// Batch submits the job via batch(1), runs when system load permits.
+	Batch() AtJob
+	// ID sets identity of the job written to marker comment of job script.
+	ID(id string) AtJob
+	// Tags adds tags of the job. requires id.
+	Tags(tags ...string) AtJob
+	// RunTime returns run time of the job in system local time.
+	RunTime() time.Time
+	// Script returns script of the job piped to at.
+	Script() string
+	// Submit submits the job. returns at job number.
+	Submit() (int, error)
+	// Pending returns pending at jobs with the job id.
+	Pending() ([]AtEntry, error)
+	// Cancel cancels pending at jobs with the job id.
+	Cancel() error
+	// Runner sets command runner of the job (default runner if nil).
+	Runner(runner Runner) AtJob
+}
+
+// AtEntry represents a pending at job.
+type AtEntry struct {
+	Number  int
+	Time    time.Time
+	Queue   string // a for at jobs, b for batch jobs
+	User    string
+	ID      string // marker id of managed jobs
+	Tags    []string
+	Command string // command of managed jobs
+	Managed bool
+}
+
+// NewAtJob creates a new one-shot job.
+func NewAtJob(command string, tz *CronTZ) AtJob {
+	job := new(atDriver)
+	job.command = command
+	job.tz = tz
+	return job
+}
+
+// ListAtJobs lists pending at jobs (atq). id, tags and command of
+// jobs submitted by gounix read from job script.
+func ListAtJobs() ([]AtEntry, error) {
+	return listAtJobs(nil)
+}
+
+// CancelAtJob cancels pending at job by number (atrm).
+func CancelAtJob(number int) error {
+	_, err := runRoot(nil, "", "atrm", strconv.Itoa(number))
+	return err
+}
+
+// ParseAtQueue parses atq output (e.g. "5\tSat Oct 17 03:00:00 2026 a root")
+// into entries. job times parsed in system local time.
+func ParseAtQueue(output string) ([]AtEntry, error) {
+	result := make([]AtEntry, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		} else if len(fields) < 8 {
+			return nil, fmt.Errorf("invalid atq line %q", line)
+		}
+
+		number, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid atq job number %q", fields[0])
+		}
+		at, err := time.ParseInLocation("Mon Jan 2 15:04:05 2006", strings.Join(fields[1:6], " "), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid atq job time %q", strings.Join(fields[1:6], " "))
+		}
+		result = append(result, AtEntry{Number: number, Time: at, Queue: fields[6], User: fields[7]})
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package gounix_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mekramy/gounix"
+)
+
+func TestParseAtQueue(t *testing.T) {
+	output := "5\tSat Oct 17 03:00:00 2026 a root\n12\tMon Nov  2 18:30:00 2026 b deploy\n"
+	entries, err := gounix.ParseAtQueue(output)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d (%v)", len(entries), err)
+	}
+
+	expected := time.Date(2026, time.November, 2, 18, 30, 0, 0, time.Local)
+	if entries[1].Number != 12 || !entries[1].Time.Equal(expected) || entries[1].Queue != "b" || entries[1].User != "deploy" {
+		t.Errorf("Expected job 12 at %s, got %+v", expected, entries[1])
+	} else {
+		t.Logf("Test passed on %+v", entries[1])
+	}
+
+	if _, err := gounix.ParseAtQueue("invalid line\n"); err == nil {
+		t.Error("Expected error on invalid atq line")
+	}
+}
+
+func TestAtJob(t *testing.T) {
+	job := gounix.NewAtJob("migrate --up", gounix.NewTZ().Location("Asia/Tehran")).
+		At(time.Date(2026, time.October, 18, 3, 0, 0, 0, time.UTC)).
+		ID("migrate").Tags("db")
+	tehran, err := time.LoadLocation("Asia/Tehran")
+	if err != nil {
+		t.Skip("Asia/Tehran timezone not available")
+	}
+	expected := time.Date(2026, time.October, 18, 3, 0, 0, 0, tehran)
+	if result := job.RunTime(); !result.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+
+	script := "# gounix:id=migrate tags=db\nmigrate --up\n"
+	if result := job.Script(); result != script {
+		t.Errorf("Expected %q, got %q", script, result)
+	}
+
+	offset := gounix.NewAtJob("migrate", gounix.NewTZ().Hour(3).Minute(30)).
+		At(time.Date(2026, time.October, 18, 3, 0, 0, 0, time.UTC))
+	expected = time.Date(2026, time.October, 17, 23, 30, 0, 0, time.Local)
+	if result := offset.RunTime(); !result.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+
+	past := gounix.NewAtJob("migrate", nil).At(time.Now().Add(-time.Hour))
+	if _, err := past.Submit(); err == nil || !strings.Contains(err.Error(), "not in future") {
+		t.Errorf("Expected error on past time, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package gounix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type atDriver struct {
+	id      string
+	tags    []string
+	command string
+	tz      *CronTZ
+	at      time.Time
+	batch   bool
+	runner  Runner
+}
+
+func (a *atDriver) Runner(runner Runner) AtJob {
+	a.runner = runner
+	return a
+}
+
+func (a *atDriver) At(t time.Time) AtJob {
+	a.at = t
+	return a
+}
+
+func (a *atDriver) Batch() AtJob {
+	a.batch = true
+	return a
+}
+
+func (a *atDriver) ID(id string) AtJob {
+	a.id = id
+	return a
+}
+
+func (a *atDriver) Tags(tags ...string) AtJob {
+	a.tags = append(a.tags, tags...)
+	return a
+}
+
+func (a *atDriver) RunTime() time.Time {
+	if a.at.IsZero() || a.tz == nil {
+		return a.at.In(time.Local)
+	}
+
+	y, m, d := a.at.Date()
+	wall := time.Date(y, m, d, a.at.Hour(), a.at.Minute(), a.at.Second(), 0, time.Local)
+	if a.tz.location != nil {
+		wall = time.Date(y, m, d, a.at.Hour(), a.at.Minute(), a.at.Second(), 0, a.tz.location)
+		return wall.In(time.Local)
+	}
+	return wall.Add(-time.Duration(a.tz.offset()) * time.Minute)
+}
+
+func (a *atDriver) Script() string {
+	script := ""
+	if a.id != "" {
+		script = markerPrefix + "id=" + a.id
+		if len(a.tags) > 0 {
+			script += " tags=" + strings.Join(a.tags, ",")
+		}
+		script += "\n"
+	}
+	return script + a.command + "\n"
+}
+
+func (a *atDriver) Submit() (int, error) {
+	// Validate job
+	if staging() {
+		return 0, errors.New("at jobs not supported on staging filesystem")
+	} else if strings.TrimSpace(a.command) == "" {
+		return 0, errors.New("empty at job command")
+	} else if strings.ContainsFunc(a.id, unicode.IsSpace) {
+		return 0, fmt.Errorf("invalid at job id %q", a.id)
+	} else if len(a.tags) > 0 && a.id == "" {
+		return 0, errors.New("at job tags requires id")
+	} else if a.tz != nil && a.tz.err != nil {
+		return 0, a.tz.err
+	}
+
+	// Submit to at or batch
+	args := []string{"batch"}
+	if !a.batch {
+		at := a.RunTime()
+		if at.IsZero() || !at.After(time.Now()) {
+			return 0, fmt.Errorf("at job time %s is not in future", at.Format(time.DateTime))
+		}
+		args = []string{"at", "-t", at.Format("200601021504.05")}
+	}
+	result, err := runRoot(a.runner, a.Script(), args...)
+	if err != nil {
+		return 0, err
+	}
+	output := result.Stderr + result.Stdout
+
+	// Parse job number (e.g. job 5 at Sat Oct 17 03:00:00 2026)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "job" {
+			if number, err := strconv.Atoi(fields[1]); err == nil {
+				return number, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown at output %q", strings.TrimSpace(output))
+}
+
+func (a *atDriver) Pending() ([]AtEntry, error) {
+	if a.id == "" {
+		return nil, errors.New("at job id required")
+	}
+
+	entries, err := listAtJobs(a.runner)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]AtEntry, 0)
+	for _, entry := range entries {
+		if entry.ID == a.id {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (a *atDriver) Cancel() error {
+	if staging() {
+		return errors.New("at jobs not supported on staging filesystem")
+	}
+	entries, err := a.Pending()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, err := runRoot(a.runner, "", "atrm", strconv.Itoa(entry.Number)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listAtJobs lists pending at jobs with runner.
+func listAtJobs(runner Runner) ([]AtEntry, error) {
+	result, err := runRoot(runner, "", "atq")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ParseAtQueue(result.Stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read managed job markers
+	for i := range entries {
+		script, err := runRoot(runner, "", "at", "-c", strconv.Itoa(entries[i].Number))
+		if err == nil {
+			parseAtScript(&entries[i], script.Stdout)
+		}
+	}
+	return entries, nil
+}
+
+// parseAtScript reads marker and command of managed job from at job script.
+func parseAtScript(entry *AtEntry, script string) {
+	lines := strings.Split(script, "\n")
+	for i, line := range lines {
+		attrs, ok := parseMarker(line)
+		if !ok || attrs["id"] == "" {
+			continue
+		}
+
+		entry.ID = attrs["id"]
+		entry.Tags = nil
+		if attrs["tags"] != "" {
+			entry.Tags = strings.Split(attrs["tags"], ",")
+		}
+		entry.Managed = true
+
+		// Command ends before at heredoc delimiter
+		command := make([]string, 0)
+		for _, line := range lines[i+1:] {
+			if strings.HasPrefix(line, "marcinDELIMITER") {
+				break
+			}
+			command = append(command, line)
+		}
+		entry.Command = strings.TrimSpace(strings.Join(command, "\n"))
+		return
+	}
+}
@@ -1,10 +1,18 @@
 package gounix
 
 import (
-	"os/exec"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"time"
 )
 
+// ErrNoRunTime is returned when cron job has no computable run time
+// (e.g. @reboot jobs or schedules that never fire).
+var ErrNoRunTime = errors.New("cron job has no run time")
+
 // CronJob represents a cron job.
 type CronJob interface {
 	// AtReboot schedules the cron job to run at reboot.
@@ -12,6 +20,8 @@ type CronJob interface {
 	// Yearly schedules the cron job to run every year.
 	Yearly() CronJob
 	// Monthly schedules the cron job to run every month.
+	// can not be translated for timezones ahead of daemon timezone,
+	// Validate and Install return *CronScheduleWarning.
 	Monthly() CronJob
 	// Weekly schedules the cron job to run every week.
 	Weekly(wd Weekday) CronJob
@@ -31,23 +41,133 @@ type CronJob interface {
 	SetMonth(month int) CronJob
 	// SetDayOfWeek sets the day of the week of the cron job.
 	SetDayOfWeek(day Weekday) CronJob
+	// Minutes sets the list of minutes of the cron job (e.g. 0,15,45).
+	Minutes(minutes ...int) CronJob
+	// Hours sets the list of hours of the cron job.
+	Hours(hours ...int) CronJob
+	// Days sets the list of days of the month of the cron job.
+	Days(days ...int) CronJob
+	// Months sets the list of months of the cron job.
+	Months(months ...int) CronJob
+	// Weekdays sets the list of days of the week of the cron job.
+	// Auto weekday replaced by timezone weekend.
+	Weekdays(days ...Weekday) CronJob
+	// MinuteRange sets the range of minutes of the cron job (e.g. 0-29).
+	MinuteRange(from, to int) CronJob
+	// HourRange sets the range of hours of the cron job (e.g. 9-17).
+	HourRange(from, to int) CronJob
+	// DayRange sets the range of days of the month of the cron job (e.g. 1-7).
+	DayRange(from, to int) CronJob
+	// MonthRange sets the range of months of the cron job (e.g. 6-8).
+	MonthRange(from, to int) CronJob
+	// WeekdayRange sets the range of days of the week of the cron job.
+	// range can wrap around end of week (e.g. Friday to Monday).
+	WeekdayRange(from, to Weekday) CronJob
+	// MinuteRangeStep sets the stepped range of minutes of the cron job (e.g. 0-30/10).
+	MinuteRangeStep(from, to, step int) CronJob
+	// HourRangeStep sets the stepped range of hours of the cron job (e.g. 9-17/2).
+	HourRangeStep(from, to, step int) CronJob
+	// DayRangeStep sets the stepped range of days of the month of the cron job (e.g. 1-31/2).
+	DayRangeStep(from, to, step int) CronJob
+	// MonthRangeStep sets the stepped range of months of the cron job (e.g. 1-12/3).
+	MonthRangeStep(from, to, step int) CronJob
 	// Command sets the command to be executed by the cron job.
 	Command(command string) CronJob
+	// Exclude skips runs of the cron job on calendar excluded dates of job
+	// timezone (holidays, freezes). guard added to command and run times
+	// calculation honors the calendar.
+	Exclude(calendar *CronCalendar) CronJob
+	// Splay delays the cron job by stable offset within window (e.g. 30m) to
+	// spread fleet-wide jobs. offset derived from seed (hostname if empty),
+	// applied before timezone translation.
+	Splay(window time.Duration, seed string) CronJob
+	// CrontabEnv sets environment variable of the cron job as crontab lines
+	// wrapped around the job (KEY=value before and previous value or daemon
+	// default restored after), so cron daemon variables (e.g. MAILTO, SHELL,
+	// PATH) apply to this job only.
+	CrontabEnv(key, value string) CronJob
+	// NoOverlap skips run while previous run of the cron job is still running
+	// (flock based). lock file placed in /run/lock for root jobs or home directory
+	// of user, symlinked lock refused. skipped runs exit with code 75.
+	NoOverlap() CronJob
+	// Timeout kills the cron job after maximum runtime (exit code 124).
+	Timeout(d time.Duration) CronJob
+	// LogFile appends stdout and stderr of the cron job to file.
+	LogFile(path string) CronJob
+	// Env sets environment variable of the cron job command.
+	Env(key, value string) CronJob
+	// RecordHistory records start time, duration and exit code of each run to
+	// JSON lines file. empty path means /var/log/gounix-<id>.jsonl for root jobs
+	// or ~/.gounix-<id>.jsonl in home directory of user.
+	RecordHistory(path string) CronJob
+	// History reads last n recorded runs of the cron job.
+	History(n int) ([]CronRun, error)
+	// Tags adds tags of managed cron job, written to marker comment
+	// (e.g. "# gounix:id=backup tags=db,nightly"). requires id.
+	Tags(tags ...string) CronJob
+	// User sets the user cron job runs as. job installed to crontab of user
+	// (crontab -u user) or used as user column of system crontab stores
+	// (/etc/cron.d). default root.
+	User(user string) CronJob
+	// Store sets crontab storage backend of the cron job (default user crontab).
+	Store(store CronStore) CronJob
+	// Runner sets command runner of the cron job (default runner if nil).
+	// used by default user crontab store and daemon restart.
+	Runner(runner Runner) CronJob
+	// ID sets stable identity of the cron job. job with id managed by
+	// "# gounix:id=<id>" marker comment and matched by id instead of command.
+	ID(id string) CronJob
 	// Compile compiles the cron job into a cron expression string.
+	// user column included for system crontab stores.
+	// CRON_TZ line of native timezone not included. location offset resolved at
+	// CronTZ.OffsetAt instant or current time. schedule that can not be
+	// translated to daemon timezone compiled untranslated and reported by Validate.
 	Compile() string
+	// NextRun returns the first run time of the cron job after specified time.
+	// compiled schedule evaluated in daemon (system local) timezone
+	// or CRON_TZ location for native timezone jobs.
+	NextRun(after time.Time) (time.Time, error)
+	// NextRuns returns the next n run times of the cron job after specified time.
+	NextRuns(after time.Time, n int) ([]time.Time, error)
+	// PrevRun returns the last run time of the cron job before specified time.
+	PrevRun(before time.Time) (time.Time, error)
+	// Describe returns human readable english description of the cron job
+	// schedule in job timezone (e.g. "At 02:30 every Friday (local+03:30)").
+	Describe() string
+	// DescribeIn returns human readable description of the cron job schedule
+	// using locale phrases (e.g. &gounix.PersianLocale).
+	DescribeIn(locale *CronLocale) string
+	// ToSystemdTimer converts the cron job into systemd timer and service units.
+	ToSystemdTimer(name string) SystemdTimer
+	// ToAnacron converts the cron job into anacron job with identifier.
+	ToAnacron(id string) AnacronJob
+	// Validate validates the cron job. returns joined *CronFieldError for
+	// invalid setters input and *CronScheduleWarning for schedules that never
+	// fire or can not be translated to daemon timezone.
+	Validate() error
 	// Exists checks if the cron job already exists.
+	// job matched by id or by command if id not set.
 	Exists() (bool, error)
-	// Install installs the cron job. returns false if cronjob exists.
+	// Install validates and installs the cron job. returns false if cronjob exists.
 	Install() (bool, error)
 	// Uninstall uninstalls the cron job.
 	Uninstall() error
 }
 
-// NewTZ creates a new timezone for a cron job.
+// NewTZ creates a new timezone for a cron job. offset ahead of daemon
+// timezone moving 1st day of month to previous month (e.g. Monthly)
+// can not be translated, use location with TZNative mode instead.
 func NewTZ() *CronTZ {
 	return new(CronTZ)
 }
 
+// NewTZFromLocation creates a new location based timezone for a cron job.
+func NewTZFromLocation(loc *time.Location) *CronTZ {
+	tz := new(CronTZ)
+	tz.location = loc
+	return tz
+}
+
 // NewCronJob creates a new cron job.
 func NewCronJob(command string, tz *CronTZ) CronJob {
 	driver := new(cronDriver)
@@ -57,19 +177,127 @@ func NewCronJob(command string, tz *CronTZ) CronJob {
 	return driver
 }
 
+// ParseCronJob parses a crontab line into a cron job.
+// line can use five fields expression with ranges, lists, steps
+// and month/day names or predefined aliases (e.g. @daily).
+func ParseCronJob(line string) (CronJob, error) {
+	if driver, err := parseCronLine(line, false); err != nil {
+		return nil, err
+	} else {
+		return driver, nil
+	}
+}
+
 // SetCronTZ sets the timezone of the cron daemon to the specified timezone.
+// existing TZ assignment updated in place and other lines kept untouched.
 func SetCronTZ(tz string) error {
-	if lines, err := allCrons(); err != nil {
+	return SetCronEnv("TZ", tz)
+}
+
+// SetCronEnv sets global environment variable of root crontab
+// (e.g. MAILTO, PATH, SHELL, CRON_TZ). existing assignment updated in place.
+func SetCronEnv(key, value string) error {
+	if tab, err := ReadCrontab(); err != nil {
+		return err
+	} else if err := tab.SetEnv(key, value); err != nil {
 		return err
 	} else {
-		var result strings.Builder
-		result.WriteString("TZ=" + tz + "\n")
-		for _, line := range lines {
-			if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "TZ=") {
-				result.WriteString(line + "\n")
+		return WriteCrontab(tab)
+	}
+}
+
+// UnsetCronEnv removes global environment variable of root crontab.
+func UnsetCronEnv(key string) error {
+	if tab, err := ReadCrontab(); err != nil {
+		return err
+	} else if removed, err := tab.UnsetEnv(key); err != nil || !removed {
+		return err
+	} else {
+		return WriteCrontab(tab)
+	}
+}
+
+// CronEnv returns global environment variables of root crontab.
+// environment blocks of cron jobs not included.
+func CronEnv() (map[string]string, error) {
+	if tab, err := ReadCrontab(); err != nil {
+		return nil, err
+	} else {
+		return tab.Envs(), nil
+	}
+}
+
+// ListCronJobs lists installed cron jobs of user crontabs and system
+// crontab files, filtered by options. root-only cron spool listed with
+// escalated command, ErrNoPrivileges returned if escalation not possible.
+func ListCronJobs(opts CronListOptions) ([]CronEntry, error) {
+	// Resolve users
+	users := opts.Users
+	if opts.AllUsers {
+		for _, dir := range []string{"/var/spool/cron/crontabs", "/var/spool/cron"} {
+			names, err := readDir(opts.Runner, dir)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("list cron spool %s, %w", dir, err)
+			}
+			for _, name := range names {
+				if !slices.Contains(users, name) {
+					users = append(users, name)
+				}
+			}
+			break
+		}
+	} else if len(users) == 0 {
+		users = []string{"root"}
+	}
+
+	// Read user crontabs
+	result := make([]CronEntry, 0)
+	for _, user := range users {
+		tab, err := (&userCrontab{user: user, runner: opts.Runner}).Read()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range tab.Entries() {
+			entry.Owner = user
+			entry.Source = "crontab:" + user
+			result = append(result, entry)
+		}
+	}
+
+	// Read system crontabs
+	if opts.System {
+		files := []string{"/etc/crontab"}
+		if names, err := readDir(opts.Runner, "/etc/cron.d"); err == nil {
+			for _, name := range names {
+				if !strings.Contains(name, ".") {
+					files = append(files, "/etc/cron.d/"+name)
+				}
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		for _, file := range files {
+			content, err := readFile(opts.Runner, file)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			for _, entry := range ParseSystemCrontab(string(content)).Entries() {
+				entry.Source = file
+				result = append(result, entry)
 			}
 		}
-		cmd := `echo "` + result.String() + `" | crontab -`
-		return exec.Command("sudo", "bash", "-c", cmd).Run()
 	}
+
+	// Filter jobs
+	return slices.DeleteFunc(result, func(entry CronEntry) bool {
+		return (opts.CommandPrefix != "" && !strings.HasPrefix(entry.Command, opts.CommandPrefix)) ||
+			(opts.ID != "" && entry.ID != opts.ID) ||
+			(opts.Tag != "" && !slices.Contains(entry.Tags, opts.Tag)) ||
+			(opts.ManagedOnly && !entry.Managed)
+	}), nil
 }
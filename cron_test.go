@@ -1,7 +1,14 @@
 package gounix_test
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mekramy/gounix"
 )
@@ -9,10 +16,10 @@ import (
 func TestCronGenerator(t *testing.T) {
 	data := map[string]gounix.CronJob{
 		"@reboot do some": gounix.NewCronJob("do some", nil).AtReboot(),
-		"30 20 * * 0 do some": gounix.
+		"30 20 * * 6 do some": gounix.
 			NewCronJob("do some", gounix.NewTZ().Hour(3).Minute(30)).
 			Weekly(gounix.Auto),
-		"0 03 * * 3 do some": gounix.
+		"0 3 * * 3 do some": gounix.
 			NewCronJob("do some", gounix.NewTZ().Hour(0).Minute(0).Weekend(gounix.Wednesday)).
 			Weekly(gounix.Auto).
 			SetHour(3).
@@ -28,3 +35,556 @@ func TestCronGenerator(t *testing.T) {
 		}
 	}
 }
+
+func TestCronTimezoneRollover(t *testing.T) {
+	tehran := gounix.NewTZ().Hour(3).Minute(30)
+	newYork := gounix.NewTZ().Hour(-5)
+	data := map[string]gounix.CronJob{
+		"30 20 * * 0 do some":     gounix.NewCronJob("do some", tehran).Weekly(gounix.Monday),
+		"30 21 14 * * do some":    gounix.NewCronJob("do some", tehran).Monthly().SetDayOfMonth(15).SetHour(1),
+		"30 20 31 12 * do some":   gounix.NewCronJob("do some", tehran).Yearly(),
+		"30 2-20/6 * * * do some": gounix.NewCronJob("do some", tehran).Daily().EveryXHours(6),
+		"30 22 * * * do some":     gounix.NewCronJob("do some", tehran).Daily().SetHour(2),
+		"0 3 * * 0 do some":       gounix.NewCronJob("do some", newYork).Weekly(gounix.Saturday).SetHour(22),
+		"0 4 1 1 * do some":       gounix.NewCronJob("do some", newYork).Yearly().SetMonth(12).SetDayOfMonth(31).SetHour(23),
+		"0 5 * * * do some":       gounix.NewCronJob("do some", newYork).Daily(),
+		"0 2 * * 1 do some":       gounix.NewCronJob("do some", gounix.NewTZ().Hour(-2)).Weekly(gounix.Monday),
+		"0 19 29 2 * do some":     gounix.NewCronJob("do some", newYork).Yearly().SetMonth(2).SetDayOfMonth(29).SetHour(14),
+		"30 20 28 * * do some":    gounix.NewCronJob("do some", tehran).Monthly().SetDayOfMonth(29),
+	}
+
+	for expected, cron := range data {
+		result := cron.Compile()
+		if result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		} else {
+			t.Logf("Test passed on %s", expected)
+		}
+	}
+
+	// Schedules can not be translated compiled untranslated
+	parsed, err := gounix.ParseCronJob("40 23 1-7 * */2 do some")
+	if err != nil {
+		t.Fatal(err)
+	}
+	untranslatable := map[string]gounix.CronJob{
+		"0 0 1 * * do some":       gounix.NewCronJob("do some", tehran).Monthly(), // march 1st shifts to ambiguous last day of february
+		"0 23 29 2 * do some":     gounix.NewCronJob("do some", gounix.NewTZ().Hour(-1)).Yearly().SetMonth(2).SetDayOfMonth(29).SetHour(23),
+		"40 23 1-7 * */2 do some": parsed.Splay(time.Hour, "host-a"), // starred weekday matched with day
+	}
+	for expected, cron := range untranslatable {
+		var warning *gounix.CronScheduleWarning
+		if err := cron.Validate(); !errors.As(err, &warning) {
+			t.Errorf("Expected schedule warning on %s, got %v", expected, err)
+		} else if result := cron.Compile(); result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		} else {
+			t.Logf("Test passed on %s", expected)
+		}
+	}
+}
+
+func TestCronRangeBuilders(t *testing.T) {
+	tehran := gounix.NewTZ().Hour(3).Minute(30)
+	newYork := gounix.NewTZ().Hour(-5)
+	data := map[string]gounix.CronJob{
+		"0,15,45 * * * * do some":   gounix.NewCronJob("do some", nil).Minutes(45, 0, 15),
+		"*/15 9-17 * * 1-5 do some": gounix.NewCronJob("do some", nil).EveryXMinutes(15).HourRange(9, 17).WeekdayRange(gounix.Monday, gounix.Friday),
+		"0 9-17/2 * * * do some":    gounix.NewCronJob("do some", nil).SetMinute(0).HourRangeStep(9, 17, 2),
+		"0 0 * * 0,1,5,6 do some":   gounix.NewCronJob("do some", nil).Daily().WeekdayRange(gounix.Friday, gounix.Monday),
+		"0 0 1-7 */3 * do some":     gounix.NewCronJob("do some", nil).Daily().DayRange(1, 7).Months(1, 4, 7, 10),
+		"0 0 * * 1,5 do some":       gounix.NewCronJob("do some", nil).Daily().Weekdays(gounix.Monday, gounix.Friday),
+		"30 5-13 * * 1-5 do some":   gounix.NewCronJob("do some", tehran).SetMinute(0).HourRange(9, 17).Weekdays(gounix.Monday, gounix.Tuesday, gounix.Wednesday, gounix.Thursday, gounix.Friday),
+		"30 20,22 * * 0,4 do some":  gounix.NewCronJob("do some", tehran).Minutes(0).Hours(0, 2).Weekdays(gounix.Monday, gounix.Friday),
+		"0 3 2-8 * 2 do some":       gounix.NewCronJob("do some", newYork).SetMinute(0).SetHour(22).DayRange(1, 7).Weekdays(gounix.Monday),
+		"0 14-22/2 * 6-8 * do some": gounix.NewCronJob("do some", newYork).SetMinute(0).HourRangeStep(9, 17, 2).MonthRange(6, 8),
+		"0 0 * * * do some":         gounix.NewCronJob("do some", nil).Daily().Minutes(75).HourRange(5, 1),
+	}
+
+	for expected, cron := range data {
+		result := cron.Compile()
+		if result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		} else {
+			t.Logf("Test passed on %s", expected)
+		}
+	}
+}
+
+func TestParseCronJob(t *testing.T) {
+	data := map[string]string{
+		"@reboot do some":                        "@reboot do some",
+		"@daily do some":                         "0 0 * * * do some",
+		"@weekly do some":                        "0 0 * * 0 do some",
+		"*/15 9-17 1,15 jan-jun/2 * do  some":    "*/15 9-17 1,15 jan-jun/2 * do  some",
+		"30 12 * * Mon-Fri /usr/bin/backup -f  ": "30 12 * * Mon-Fri /usr/bin/backup -f",
+	}
+
+	for line, expected := range data {
+		cron, err := gounix.ParseCronJob(line)
+		if err != nil {
+			t.Errorf("Failed to parse %s: %v", line, err)
+		} else if result := cron.Compile(); result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		} else {
+			t.Logf("Test passed on %s", expected)
+		}
+	}
+
+	invalids := []string{
+		"",
+		"# comment",
+		"MAILTO=root",
+		"61 * * * * do some",
+		"* * * * *",
+		"5-1 * * * * do some",
+		"* * * foo * do some",
+		"@never do some",
+	}
+	for _, line := range invalids {
+		if _, err := gounix.ParseCronJob(line); err == nil {
+			t.Errorf("Expected error on %q", line)
+		}
+	}
+}
+
+func TestCronDayFields(t *testing.T) {
+	odd := []int{1, 3, 5, 7, 9, 11, 13, 15, 17, 19, 21, 23, 25, 27, 29, 31}
+	all := []gounix.Weekday{gounix.Sunday, gounix.Monday, gounix.Tuesday, gounix.Wednesday, gounix.Thursday, gounix.Friday, gounix.Saturday}
+	data := map[string]gounix.CronJob{
+		"0 0 1-7 * 0-6/2 do some": gounix.NewCronJob("do some", nil).Daily().Weekdays(gounix.Sunday, gounix.Tuesday, gounix.Thursday, gounix.Saturday).DayRange(1, 7),
+		"0 0 1-7 * 0,2 do some":   gounix.NewCronJob("do some", nil).Daily().Weekdays(gounix.Sunday, gounix.Tuesday).DayRange(1, 7),
+		"0 0 1-7 * 0-6 do some":   gounix.NewCronJob("do some", nil).Daily().Weekdays(all...).DayRange(1, 7),
+		"0 0 1-31/2 * 1 do some":  gounix.NewCronJob("do some", nil).Daily().Days(odd...).SetDayOfWeek(gounix.Monday),
+		"0 0 1-31/2 * 2 do some":  gounix.NewCronJob("do some", nil).Daily().DayRangeStep(1, 31, 2).SetDayOfWeek(gounix.Tuesday),
+	}
+	for expected, cron := range data {
+		if result := cron.Compile(); result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		} else {
+			t.Logf("Test passed on %s", expected)
+		}
+	}
+
+	// Restricted day and weekday matches either one
+	after := time.Date(2025, time.January, 8, 0, 0, 0, 0, time.Local)
+	cron := gounix.NewCronJob("do some", nil).Daily().Weekdays(gounix.Sunday, gounix.Tuesday, gounix.Thursday, gounix.Saturday).DayRange(1, 7)
+	expected := []time.Time{
+		time.Date(2025, time.January, 9, 0, 0, 0, 0, time.Local),
+		time.Date(2025, time.January, 11, 0, 0, 0, 0, time.Local),
+		time.Date(2025, time.January, 12, 0, 0, 0, 0, time.Local),
+	}
+	if runs, err := cron.NextRuns(after, 3); err != nil || !slices.Equal(runs, expected) {
+		t.Errorf("Expected %v, got %v (%v)", expected, runs, err)
+	}
+}
+
+func TestCronNextRun(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.January, day, hour, minute, 0, 0, time.Local)
+	}
+	parse := func(line string) gounix.CronJob {
+		cron, err := gounix.ParseCronJob(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cron
+	}
+
+	data := map[string]struct {
+		cron     gounix.CronJob
+		after    time.Time
+		expected time.Time
+	}{
+		"daily": {
+			gounix.NewCronJob("do some", nil).Daily().SetHour(2).SetMinute(30),
+			at(1, 2, 30), at(2, 2, 30),
+		},
+		"daily with timezone": {
+			gounix.NewCronJob("do some", gounix.NewTZ().Hour(3).Minute(30)).Daily().SetHour(12).SetMinute(30),
+			at(1, 0, 0), at(1, 9, 0),
+		},
+		"day of month or weekday": {
+			parse("0 0 13 * fri do some"),
+			at(1, 0, 0), at(3, 0, 0),
+		},
+		"steps and ranges": {
+			parse("*/20 9-17 * * * do some"),
+			at(1, 17, 40), at(2, 9, 0),
+		},
+	}
+
+	for name, test := range data {
+		result, err := test.cron.NextRun(test.after)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if !result.Equal(test.expected) {
+			t.Errorf("%s: expected %s, got %s", name, test.expected, result)
+		} else {
+			t.Logf("Test passed on %s", name)
+		}
+	}
+
+	runs, err := parse("30 2 * * * do some").NextRuns(at(1, 0, 0), 3)
+	if err != nil || len(runs) != 3 || !runs[2].Equal(at(3, 2, 30)) {
+		t.Errorf("Expected 3 runs until %s, got %v (%v)", at(3, 2, 30), runs, err)
+	}
+
+	prev, err := parse("30 2 * * * do some").PrevRun(at(1, 2, 30))
+	if expected := time.Date(2024, time.December, 31, 2, 30, 0, 0, time.Local); err != nil || !prev.Equal(expected) {
+		t.Errorf("Expected %s, got %s (%v)", expected, prev, err)
+	}
+
+	for _, cron := range []gounix.CronJob{parse("@reboot do some"), parse("0 0 31 2 * do some")} {
+		if _, err := cron.NextRun(at(1, 0, 0)); !errors.Is(err, gounix.ErrNoRunTime) {
+			t.Errorf("Expected ErrNoRunTime on %s, got %v", cron.Compile(), err)
+		}
+	}
+}
+
+func TestCronLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("Europe/Berlin timezone not available")
+	}
+	winter := time.Date(2025, time.January, 15, 0, 0, 0, 0, berlin)
+	summer := time.Date(2025, time.July, 15, 0, 0, 0, 0, berlin)
+	at := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 2, 30, 0, 0, berlin)
+	}
+
+	// Translate mode targets offset effective at compile time
+	for _, ref := range []time.Time{winter, summer} {
+		cron := gounix.NewCronJob("do some", gounix.NewTZFromLocation(berlin).OffsetAt(ref)).
+			Daily().SetHour(2).SetMinute(30)
+		if next, err := cron.NextRun(ref); err != nil || !next.Equal(at(ref)) {
+			t.Errorf("Expected %s, got %s (%v)", at(ref), next, err)
+		}
+	}
+
+	// Translated job drifts after transition until re-installed
+	offset := func(t time.Time) time.Duration {
+		_, a := t.In(berlin).Zone()
+		_, b := t.In(time.Local).Zone()
+		return time.Duration(a-b) * time.Second
+	}
+	stale := gounix.NewCronJob("do some", gounix.NewTZ().Location("Europe/Berlin").OffsetAt(winter)).
+		Daily().SetHour(2).SetMinute(30)
+	drifted := at(summer).Add(offset(summer) - offset(winter))
+	if next, err := stale.NextRun(summer); err != nil || !next.Equal(drifted) {
+		t.Errorf("Expected %s, got %s (%v)", drifted, next, err)
+	}
+
+	// Compiled at pinned instant regardless of current time
+	for _, ref := range []time.Time{winter, summer} {
+		local := at(ref).In(time.Local)
+		expected := fmt.Sprintf("%d %d * * * do some", local.Minute(), local.Hour())
+		cron := gounix.NewCronJob("do some", gounix.NewTZFromLocation(berlin).OffsetAt(ref)).
+			Daily().SetHour(2).SetMinute(30)
+		if result := cron.Compile(); result != expected {
+			t.Errorf("Expected %s, got %s (%v)", expected, result, err)
+		} else {
+			t.Logf("Test passed on %s", result)
+		}
+	}
+
+	transition := time.Date(2025, time.March, 30, 1, 0, 0, 0, time.UTC)
+	if next, ok := gounix.NewTZFromLocation(berlin).NextTransition(winter); !ok || !next.Equal(transition) {
+		t.Errorf("Expected transition at %s, got %s", transition, next)
+	}
+
+	// Native mode keeps schedule in location time
+	native := gounix.NewCronJob("do some", gounix.NewTZFromLocation(berlin).Mode(gounix.TZNative)).
+		Daily().SetHour(2).SetMinute(30)
+	if result := native.Compile(); result != "30 2 * * * do some" {
+		t.Errorf("Expected 30 2 * * * do some, got %s", result)
+	}
+	for _, ref := range []time.Time{winter, summer} {
+		if next, err := native.NextRun(ref); err != nil || !next.Equal(at(ref)) {
+			t.Errorf("Expected %s, got %s (%v)", at(ref), next, err)
+		}
+	}
+
+	if _, err := gounix.NewCronJob("do some", gounix.NewTZ().Location("Invalid/Zone")).Runner(gounix.NewRecordingRunner()).Install(); err == nil {
+		t.Error("Expected error on invalid location")
+	}
+}
+
+func TestCronValidate(t *testing.T) {
+	fields := map[string]gounix.CronJob{
+		"minute":       gounix.NewCronJob("do some", nil).SetMinute(75),
+		"hour":         gounix.NewCronJob("do some", nil).HourRange(17, 9),
+		"day of month": gounix.NewCronJob("do some", nil).SetDayOfMonth(0),
+		"month":        gounix.NewCronJob("do some", nil).Months(13),
+		"day of week":  gounix.NewCronJob("do some", nil).SetDayOfWeek(gounix.Weekday(9)),
+	}
+	for field, cron := range fields {
+		var fieldErr *gounix.CronFieldError
+		if err := cron.Validate(); !errors.As(err, &fieldErr) || fieldErr.Field != field {
+			t.Errorf("Expected %s field error, got %v", field, err)
+		} else {
+			t.Logf("Test passed on %s", err)
+		}
+	}
+
+	step := gounix.NewCronJob("do some", nil).EveryXMinutes(0)
+	if result := step.Compile(); result != "* * * * * do some" {
+		t.Errorf("Expected invalid step ignored, got %s", result)
+	}
+	if err := step.Validate(); err == nil {
+		t.Error("Expected error on zero step")
+	}
+
+	warnings := []gounix.CronJob{
+		gounix.NewCronJob("do some", nil).Yearly().SetMonth(2).SetDayOfMonth(31),
+		gounix.NewCronJob("do some", gounix.NewTZ().Hour(3).Minute(30)).Monthly(),
+	}
+	runner := gounix.NewRecordingRunner()
+	for _, cron := range warnings {
+		var warning *gounix.CronScheduleWarning
+		if err := cron.Validate(); !errors.As(err, &warning) {
+			t.Errorf("Expected schedule warning on %s, got %v", cron.Compile(), err)
+		} else if _, err := cron.Runner(runner).Install(); err == nil || len(runner.Commands()) != 0 {
+			t.Errorf("Expected install error on %s, got %v", cron.Compile(), runner.Commands())
+		}
+	}
+
+	valid := []gounix.CronJob{
+		gounix.NewCronJob("do some", nil).SetMinute(75).SetMinute(5),
+		gounix.NewCronJob("do some", nil).Yearly().SetMonth(2).SetDayOfMonth(29),
+		gounix.NewCronJob("do some", nil).Monthly().SetDayOfMonth(31).Weekdays(gounix.Monday).SetMonth(2),
+		gounix.NewCronJob("do some", nil).AtReboot(),
+	}
+	for _, cron := range valid {
+		if err := cron.Validate(); err != nil {
+			t.Errorf("Expected valid %s, got %v", cron.Compile(), err)
+		}
+	}
+
+	// Wrapped job identity independent of wrapper options
+	if err := gounix.NewCronJob("do some", nil).NoOverlap().Validate(); err == nil {
+		t.Error("Expected error on wrapper without id")
+	}
+}
+
+func TestCronSplay(t *testing.T) {
+	tehran := gounix.NewTZ().Hour(3).Minute(30)
+	crons := map[string]gounix.CronJob{
+		"21 0 * * * do some":      gounix.NewCronJob("do some", nil).Daily().Splay(time.Hour, "host-a"),
+		"24 0 * * * do some":      gounix.NewCronJob("do some", nil).Daily().Splay(time.Hour, "host-b"),
+		"51 20 * * * do some":     gounix.NewCronJob("do some", tehran).Daily().Splay(2*time.Hour, "host-a"),
+		"9-54/15 * * * * do some": gounix.NewCronJob("do some", nil).EveryXMinutes(15).Splay(15*time.Minute, "host-b"),
+		"1 0 * * 6 do some":       gounix.NewCronJob("do some", nil).Weekly(gounix.Friday).SetHour(23).SetMinute(40).Splay(time.Hour, "host-a"),
+		"0 0 * * * do some":       gounix.NewCronJob("do some", nil).Daily().Splay(0, "host-a"),
+	}
+	for expected, cron := range crons {
+		if result := cron.Compile(); result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		} else {
+			t.Logf("Test passed on %s", result)
+		}
+	}
+
+	host := gounix.NewCronJob("do some", nil).Daily().Splay(time.Hour, "").Compile()
+	if result := gounix.NewCronJob("do some", nil).Daily().Splay(time.Hour, "").Compile(); result != host {
+		t.Errorf("Expected stable hostname splay %s, got %s", host, result)
+	}
+	if err := gounix.NewCronJob("do some", nil).Daily().Splay(48*time.Hour, "").Validate(); err == nil {
+		t.Error("Expected error on splay window out of range")
+	}
+}
+
+func TestCronDescribe(t *testing.T) {
+	tehran := gounix.NewTZ().Hour(3).Minute(30).Weekend(gounix.Friday)
+	berlin := gounix.NewTZ().Location("Europe/Berlin")
+	crons := map[string]gounix.CronJob{
+		"At 02:30 every Friday (local+03:30)":                               gounix.NewCronJob("do some", tehran).Weekly(gounix.Auto).SetHour(2).SetMinute(30),
+		"Every 15 minutes between 09:00 and 17:59 on weekdays":              gounix.NewCronJob("do some", nil).EveryXMinutes(15).HourRange(9, 17).WeekdayRange(gounix.Monday, gounix.Friday),
+		"At 00:00 and 12:00 on weekdays (local+03:30)":                      gounix.NewCronJob("do some", tehran).Hours(0, 12).SetMinute(0).WeekdayRange(gounix.Saturday, gounix.Thursday),
+		"At minute 0 past every 2 hours":                                    gounix.NewCronJob("do some", nil).EveryXHours(2).SetMinute(0),
+		"Every minute, every 2 hours":                                       gounix.NewCronJob("do some", nil).EveryXHours(2),
+		"At minute 0, 15 and 45 past every hour on day 1 to 7 of the month": gounix.NewCronJob("do some", nil).Minutes(0, 15, 45).DayRange(1, 7),
+		"At 00:00 on day 1 of the month in January (Europe/Berlin)":         gounix.NewCronJob("do some", berlin).Yearly(),
+		"At 08:00 on Monday and Wednesday or on day 15 of the month":        gounix.NewCronJob("do some", nil).Daily().SetHour(8).Weekdays(gounix.Monday, gounix.Wednesday).SetDayOfMonth(15),
+		"At system startup":                                                 gounix.NewCronJob("do some", nil).AtReboot(),
+	}
+	for expected, cron := range crons {
+		if result := cron.Describe(); result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		} else {
+			t.Logf("Test passed on %s", result)
+		}
+	}
+
+	// Starred day field matched with weekday
+	parsed, err := gounix.ParseCronJob("0 8 */10 * 1 do some")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := parsed.Describe(); result != "At 08:00 every Monday and on day 1, 11, 21 and 31 of the month" {
+		t.Errorf("Expected and joined days, got %s", result)
+	}
+
+	expected := "هر دقیقه"
+	if result := gounix.NewCronJob("do some", nil).DescribeIn(&gounix.PersianLocale); result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestCronSystemdTimer(t *testing.T) {
+	berlin := gounix.NewTZ().Location("Europe/Berlin")
+	crons := map[string]gounix.CronJob{
+		"*-*-* 02:30:00":                           gounix.NewCronJob("do some", nil).Daily().SetHour(2).SetMinute(30),
+		"Mon..Fri *-*-* 09..17:00/15:00":           gounix.NewCronJob("do some", nil).EveryXMinutes(15).HourRange(9, 17).WeekdayRange(gounix.Monday, gounix.Friday),
+		"*-01,07-01 00:00:00 Europe/Berlin":        gounix.NewCronJob("do some", berlin).Monthly().Months(1, 7),
+		"Sun,Sat *-*-* 00:00:00|*-*-01 00:00:00":   gounix.NewCronJob("do some", nil).Daily().Weekdays(gounix.Saturday, gounix.Sunday).SetDayOfMonth(1),
+		"Mon *-*-* 00/6:00:00|*-*-01/2 00/6:00:00": gounix.NewCronJob("do some", nil).Daily().EveryXHours(6).DayRangeStep(1, 31, 2).SetDayOfWeek(gounix.Monday),
+		"*-*-* 20:30:00":                           gounix.NewCronJob("do some", gounix.NewTZ().Hour(3).Minute(30)).Daily(),
+		"":                                         gounix.NewCronJob("do some", nil).AtReboot(),
+	}
+	for expected, cron := range crons {
+		calendars, err := cron.ToSystemdTimer("backup").OnCalendar()
+		if result := strings.Join(calendars, "|"); err != nil || result != expected {
+			t.Errorf("Expected %s, got %s (%v)", expected, result, err)
+		} else {
+			t.Logf("Test passed on %s", result)
+		}
+	}
+
+	timer := gounix.NewCronJob("echo \"$HOME\" 100%", nil).Daily().
+		ToSystemdTimer("backup").RandomizedDelay(5 * time.Minute).Accuracy(time.Second)
+	units := []string{"OnCalendar=*-*-* 00:00:00", "Persistent=true", "RandomizedDelaySec=300s", "AccuracySec=1s", "Unit=backup.service"}
+	content, err := timer.Timer()
+	for _, unit := range units {
+		if err != nil || !strings.Contains(content, unit+"\n") {
+			t.Errorf("Expected %s in timer, got %s", unit, content)
+		}
+	}
+	expected := `ExecStart=/bin/sh -c "echo \"$$HOME\" 100%%"`
+	if result := timer.Service(); !strings.Contains(result, expected) {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestCronAnacron(t *testing.T) {
+	crons := map[string]gounix.CronJob{
+		"1\t5\tbackup\tdo some":        gounix.NewCronJob("do some", nil).Daily().SetHour(3),
+		"7\t5\tbackup\tdo some":        gounix.NewCronJob("do some", nil).Weekly(gounix.Friday),
+		"@monthly\t5\tbackup\tdo some": gounix.NewCronJob("do some", nil).Monthly(),
+		"365\t5\tbackup\tdo some":      gounix.NewCronJob("do some", nil).Yearly().User("root"),
+	}
+	for expected, cron := range crons {
+		if result, err := cron.ToAnacron("backup").Compile(); err != nil || result != expected {
+			t.Errorf("Expected %q, got %q (%v)", expected, result, err)
+		} else {
+			t.Logf("Test passed on %q", result)
+		}
+	}
+
+	expected := "3\t15\tbackup\tdo some"
+	job := gounix.NewCronJob("do some", nil).EveryXHours(2).ToAnacron("backup").Period(3).Delay(15 * time.Minute)
+	if result, err := job.Compile(); err != nil || result != expected {
+		t.Errorf("Expected %q, got %q (%v)", expected, result, err)
+	}
+
+	invalid := []gounix.AnacronJob{
+		gounix.NewCronJob("do some", nil).EveryXHours(2).ToAnacron("backup"),
+		gounix.NewCronJob("do some", nil).AtReboot().ToAnacron("backup"),
+		gounix.NewCronJob("do some", nil).Daily().ToAnacron("back up"),
+		gounix.NewCronJob("do some", nil).Daily().User("deploy").ToAnacron("backup"),
+		gounix.NewCronJob("do some", nil).Daily().Env("APP", "a\nb").ToAnacron("backup"),
+		gounix.NewCronJob("do some", nil).Daily().NoOverlap().ToAnacron("backup"),
+	}
+	for _, job := range invalid {
+		if _, err := job.Compile(); err == nil {
+			t.Error("Expected error on invalid anacron job")
+		}
+	}
+}
+
+func TestCronCalendar(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Christmas\r\nDTSTART;VALUE=DATE:20261225\r\n" +
+		"DTEND;VALUE=DATE:20261227\r\nEND:VEVENT\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20260101\r\n" +
+		"RRULE:FREQ=YEARLY\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	calendar := gounix.NewCronCalendar().
+		ImportICS(strings.NewReader(ics)).
+		Date(2026, time.March, 20)
+	if err := calendar.Err(); err != nil {
+		t.Fatalf("Expected calendar imported, got %v", err)
+	}
+
+	job := gounix.NewCronJob("do some", nil).Daily().SetHour(2).Exclude(calendar)
+	expected := `0 2 * * * case "$(date +\%Y-\%m-\%d)" in *-01-01|2026-03-20|2026-12-25|2026-12-26) exit 0;; esac; do some`
+	if result := job.Compile(); result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+
+	runs, err := job.NextRuns(time.Date(2026, time.December, 24, 12, 0, 0, 0, time.Local), 3)
+	days := []int{27, 28, 29}
+	for i, run := range runs {
+		if err != nil || run.Day() != days[i] {
+			t.Errorf("Expected run on day %d, got %s (%v)", days[i], run, err)
+		}
+	}
+	if next, err := job.NextRun(time.Date(2026, time.December, 31, 12, 0, 0, 0, time.Local)); err != nil || next.Day() != 2 {
+		t.Errorf("Expected recurring new year skipped, got %s (%v)", next, err)
+	}
+	if prev, err := job.PrevRun(time.Date(2026, time.December, 27, 0, 0, 0, 0, time.Local)); err != nil || prev.Day() != 24 {
+		t.Errorf("Expected previous run before holidays, got %s (%v)", prev, err)
+	}
+
+	berlin := gounix.NewCronJob("do some", gounix.NewTZ().Location("Europe/Berlin").Mode(gounix.TZNative)).Exclude(gounix.NewCronCalendar().Recurring(time.May, 1))
+	if result := berlin.Compile(); !strings.Contains(result, `case "$(TZ='Europe/Berlin' date +\%Y-\%m-\%d)" in *-05-01) exit 0;; esac; do some`) {
+		t.Errorf("Expected location guard, got %s", result)
+	}
+	if err := gounix.NewCronJob("do some", nil).Exclude(gounix.NewCronCalendar().Recurring(time.February, 30)).Validate(); err == nil {
+		t.Error("Expected error on invalid recurring date")
+	}
+	long := gounix.NewCronCalendar().Range(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.Local), time.Date(2026, time.June, 30, 0, 0, 0, 0, time.Local))
+	if err := gounix.NewCronJob("do some", nil).ID("long").Exclude(long).Validate(); err == nil {
+		t.Error("Expected error on command exceeding cron limit")
+	}
+}
+
+func TestCronWrapper(t *testing.T) {
+	for _, bin := range []string{"sh", "flock", "timeout"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skip(bin + " not available")
+		}
+	}
+
+	dir := t.TempDir()
+	cron := gounix.NewCronJob(`echo "$GREETING it's 100%"; exit 3`, nil).
+		ID("wrapper-test").
+		NoOverlap().
+		Timeout(time.Minute).
+		LogFile(dir+"/job.log").
+		Env("GREETING", "hello").
+		RecordHistory(dir + "/history.jsonl")
+
+	// Run command as cron daemon does, lock moved from host /run/lock
+	// into test directory so root and non-root runs behave same
+	command := strings.SplitN(cron.Compile(), " ", 6)[5]
+	if lock := "'/run/lock/gounix-wrapper-test.lock'"; !strings.Contains(command, lock) {
+		t.Fatalf("Expected lock %s, got %s", lock, command)
+	}
+	command = strings.ReplaceAll(command, "/run/lock/", dir+"/")
+	command = strings.ReplaceAll(command, `\%`, "%")
+	for i := 0; i < 2; i++ {
+		if err := exec.Command("sh", "-c", command).Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if log, err := os.ReadFile(dir + "/job.log"); err != nil || string(log) != "hello it's 100%\nhello it's 100%\n" {
+		t.Errorf("Unexpected log %q (%v)", log, err)
+	}
+
+	runs, err := cron.History(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].ExitCode != 3 || time.Since(runs[0].Start) > time.Minute {
+		t.Errorf("Unexpected history %v", runs)
+	}
+}
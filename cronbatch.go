@@ -0,0 +1,25 @@
+package gounix
+
+// CronBatch stages many cron job installs and removals and applies
+// them with single crontab write under lock.
+type CronBatch interface {
+	// Install stages install or update of the cron job.
+	Install(job CronJob) CronBatch
+	// Uninstall stages removal of the cron job.
+	Uninstall(job CronJob) CronBatch
+	// Runner sets command runner of the batch (default runner if nil).
+	// used by default root crontab store and daemon restart.
+	Runner(runner Runner) CronBatch
+	// Apply validates staged jobs and applies them in one crontab write.
+	// nothing written if any job fails validation.
+	// returns result of each staged action in order.
+	Apply() ([]CronBatchResult, error)
+}
+
+// NewCronBatch creates cron batch on crontab store.
+// nil store means root crontab. store of staged jobs ignored.
+func NewCronBatch(store CronStore) CronBatch {
+	batch := new(cronBatchDriver)
+	batch.store = store
+	return batch
+}
@@ -0,0 +1,88 @@
+package gounix
+
+import (
+	"errors"
+	"fmt"
+)
+
+type cronBatchDriver struct {
+	store   CronStore
+	runner  Runner
+	results []CronBatchResult
+}
+
+// crontab get crontab storage backend.
+// default is root crontab. cron.d files use runner of batch.
+func (b *cronBatchDriver) crontab() CronStore {
+	if file, ok := b.store.(*cronDFile); ok && file.runner == nil {
+		return &cronDFile{name: file.name, runner: b.runner}
+	} else if b.store != nil {
+		return b.store
+	}
+	return &userCrontab{runner: b.runner}
+}
+
+func (b *cronBatchDriver) Runner(runner Runner) CronBatch {
+	b.runner = runner
+	return b
+}
+
+func (b *cronBatchDriver) Install(job CronJob) CronBatch {
+	b.results = append(b.results, CronBatchResult{Job: job, Action: CronBatchInstall})
+	return b
+}
+
+func (b *cronBatchDriver) Uninstall(job CronJob) CronBatch {
+	b.results = append(b.results, CronBatchResult{Job: job, Action: CronBatchUninstall})
+	return b
+}
+
+func (b *cronBatchDriver) Apply() ([]CronBatchResult, error) {
+	results := append([]CronBatchResult(nil), b.results...)
+
+	// Validate staged jobs
+	errs := make([]error, 0)
+	for i, result := range results {
+		if result.Job == nil {
+			results[i].Err = errors.New("nil cron job")
+		} else if result.Action == CronBatchInstall {
+			results[i].Err = result.Job.Validate()
+		}
+		if results[i].Err != nil {
+			errs = append(errs, fmt.Errorf("cron batch job %d: %w", i+1, results[i].Err))
+		}
+	}
+	if len(errs) > 0 {
+		return results, errors.Join(errs...)
+	}
+
+	// Read cron jobs under lock
+	unlock, err := lockCrontab(b.crontab(), b.runner)
+	if err != nil {
+		return results, err
+	}
+	defer unlock()
+	tab, err := b.crontab().Read()
+	if err != nil {
+		return results, err
+	}
+
+	// Apply staged actions
+	for i, result := range results {
+		if result.Action == CronBatchInstall {
+			results[i].Existed, err = tab.Set(result.Job)
+		} else {
+			results[i].Existed, err = tab.Remove(result.Job)
+		}
+		if err != nil {
+			return results, err
+		}
+	}
+	err = b.crontab().Write(tab)
+	if err != nil {
+		return results, err
+	}
+
+	// Restart cron service if required
+	return results, reloadCron(b.crontab(), b.runner)
+}
@@ -0,0 +1,164 @@
+package gounix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// calendarMaxDays maximum days of excluded date range.
+const calendarMaxDays = 5 * 366
+
+// CronCalendar represents exclusion calendar (holidays, maintenance
+// freezes) of cron jobs. dates evaluated in cron job timezone.
+type CronCalendar struct {
+	dates     map[string]bool // YYYY-MM-DD
+	recurring map[string]bool // MM-DD every year
+	err       error
+}
+
+// NewCronCalendar creates a new empty exclusion calendar.
+func NewCronCalendar() *CronCalendar {
+	calendar := new(CronCalendar)
+	calendar.dates = make(map[string]bool)
+	calendar.recurring = make(map[string]bool)
+	return calendar
+}
+
+// Date excludes single date.
+func (c *CronCalendar) Date(year int, month time.Month, day int) *CronCalendar {
+	c.dates[time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)] = true
+	return c
+}
+
+// Range excludes dates from first to last (inclusive), e.g. maintenance freeze.
+func (c *CronCalendar) Range(from, to time.Time) *CronCalendar {
+	first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if last.Before(first) || last.Sub(first) > calendarMaxDays*24*time.Hour {
+		c.err = fmt.Errorf("invalid calendar range %s to %s", first.Format(time.DateOnly), last.Format(time.DateOnly))
+		return c
+	}
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		c.dates[d.Format(time.DateOnly)] = true
+	}
+	return c
+}
+
+// Recurring excludes date every year (e.g. January 1).
+func (c *CronCalendar) Recurring(month time.Month, day int) *CronCalendar {
+	if month < time.January || month > time.December || day < 1 || day > monthDays[month][1] {
+		c.err = fmt.Errorf("invalid recurring calendar date %d-%d", month, day)
+		return c
+	}
+	c.recurring[fmt.Sprintf("%02d-%02d", month, day)] = true
+	return c
+}
+
+// ImportICS imports all-day events of iCalendar content as excluded dates.
+// yearly recurring events (RRULE:FREQ=YEARLY) imported as recurring dates.
+func (c *CronCalendar) ImportICS(r io.Reader) *CronCalendar {
+	// Unfold continuation lines
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+		} else {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		c.err = err
+		return c
+	}
+
+	// Parse events
+	var start, end time.Time
+	var yearly, event bool
+	for _, line := range lines {
+		name, value, _ := strings.Cut(line, ":")
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			start, end, yearly, event = time.Time{}, time.Time{}, false, true
+		case name == "DTSTART" && event:
+			start = icsDate(value)
+		case name == "DTEND" && event:
+			end = icsDate(value)
+		case name == "RRULE" && event:
+			yearly = slices.Contains(strings.Split(strings.ToUpper(value), ";"), "FREQ=YEARLY")
+		case name == "END" && strings.EqualFold(value, "VEVENT") && event:
+			event = false
+			if start.IsZero() {
+				c.err = fmt.Errorf("invalid calendar event without DTSTART")
+				continue
+			}
+
+			// DTEND of all-day events exclusive
+			last := start
+			if end.After(start) {
+				last = end.AddDate(0, 0, -1)
+			}
+			if yearly {
+				for d := start; !d.After(last); d = d.AddDate(0, 0, 1) {
+					c.Recurring(d.Month(), d.Day())
+				}
+			} else {
+				c.Range(start, last)
+			}
+		}
+	}
+	return c
+}
+
+// ImportICSFile imports all-day events of iCalendar file as excluded dates.
+func (c *CronCalendar) ImportICSFile(path string) *CronCalendar {
+	file, err := os.Open(path)
+	if err != nil {
+		c.err = err
+		return c
+	}
+	defer file.Close()
+	return c.ImportICS(file)
+}
+
+// Excluded checks if date of t is excluded. date of t evaluated in t location.
+func (c *CronCalendar) Excluded(t time.Time) bool {
+	return c.dates[t.Format(time.DateOnly)] || c.recurring[t.Format("01-02")]
+}
+
+// Err returns first error of calendar definition or import.
+func (c *CronCalendar) Err() error {
+	return c.err
+}
+
+// patterns get shell case patterns of excluded dates.
+func (c *CronCalendar) patterns() []string {
+	result := make([]string, 0, len(c.dates)+len(c.recurring))
+	for date := range c.dates {
+		result = append(result, date)
+	}
+	for date := range c.recurring {
+		result = append(result, "*-"+date)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// icsDate parses iCalendar date or date-time value into date.
+func icsDate(value string) time.Time {
+	if len(value) < 8 {
+		return time.Time{}
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
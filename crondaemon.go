@@ -0,0 +1,155 @@
+package gounix
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// CronRestart determines when cron daemon restarted after crontab changes.
+type CronRestart int
+
+const (
+	// CronRestartAuto restarts daemon only when it can not detect changes itself
+	// (files written without crontab command on daemons not watching them).
+	// crontab command and /etc/cron.d changes detected by cron and cronie.
+	CronRestartAuto CronRestart = 0
+	// CronRestartAlways restarts daemon after each change.
+	CronRestartAlways CronRestart = 1
+	// CronRestartNever never restarts daemon (e.g. containers without init system).
+	CronRestartNever CronRestart = 2
+)
+
+// cronRestart restart policy of crontab changes.
+var cronRestart = CronRestartAuto
+
+// CronDaemon represents installed cron daemon.
+type CronDaemon struct {
+	Name    string // cron (debian vixie cron), cronie or busybox
+	Binary  string // daemon binary path
+	Service string // init service name (cron or crond)
+	Init    string // systemd, openrc or empty if no init system detected
+}
+
+// SetCronRestart sets restart policy of cron daemon after crontab changes
+// (default CronRestartAuto).
+func SetCronRestart(policy CronRestart) {
+	cronRestart = policy
+}
+
+// DetectCronDaemon detects installed cron daemon, service name and init system.
+func DetectCronDaemon() (CronDaemon, error) {
+	return detectCronDaemon(nil)
+}
+
+// RestartCron restarts detected cron daemon using init system.
+func RestartCron() error {
+	return restartCron(nil)
+}
+
+// detectCronDaemon detects cron daemon with runner. checks run by runner
+// so remote hosts detected instead of local machine.
+func detectCronDaemon(runner Runner) (CronDaemon, error) {
+	var daemon CronDaemon
+
+	// Detect init system
+	if _, err := run(runner, "", "test", "-d", "/run/systemd/system"); err == nil {
+		daemon.Init = "systemd"
+	} else if _, ok := lookPath(runner, "rc-service"); ok {
+		daemon.Init = "openrc"
+	}
+
+	// Detect daemon binary, busybox crond usually symlinked to busybox
+	if path, ok := lookPath(runner, "crond"); ok {
+		daemon.Binary = path
+		daemon.Service = "crond"
+		daemon.Name = "cronie"
+		if result, err := run(runner, "", "readlink", "-f", path); err == nil &&
+			strings.Contains(filepath.Base(strings.TrimSpace(result.Stdout)), "busybox") {
+			daemon.Name = "busybox"
+		}
+		return daemon, nil
+	} else if path, ok := lookPath(runner, "cron"); ok {
+		daemon.Binary = path
+		daemon.Service = "cron"
+		daemon.Name = "cron"
+		return daemon, nil
+	} else if path, ok := lookPath(runner, "busybox"); ok && busyboxCrond(runner, path) {
+		daemon.Binary = path
+		daemon.Service = "crond"
+		daemon.Name = "busybox"
+		return daemon, nil
+	}
+	return daemon, errors.New("cron daemon not found")
+}
+
+// lookPath finds absolute path of command with runner. sbin directories
+// checked too, they are not in PATH of non-root users (e.g. /usr/sbin/cron).
+func lookPath(runner Runner, name string) (string, bool) {
+	result, err := run(runner, "", "sh", "-c", "command -v "+shellQuote(name))
+	if path := strings.TrimSpace(result.Stdout); err == nil && filepath.IsAbs(path) {
+		return path, true
+	}
+	for _, dir := range []string{"/usr/sbin", "/sbin"} {
+		if _, err := run(runner, "", "test", "-x", dir+"/"+name); err == nil {
+			return dir + "/" + name, true
+		}
+	}
+	return "", false
+}
+
+// restartCron restarts detected cron daemon with runner.
+func restartCron(runner Runner) error {
+	daemon, err := detectCronDaemon(runner)
+	if err != nil {
+		return err
+	}
+
+	switch daemon.Init {
+	case "systemd":
+		_, err := runRoot(runner, "", "systemctl", "restart", daemon.Service)
+		return err
+	case "openrc":
+		_, err := runRoot(runner, "", "rc-service", daemon.Service, "restart")
+		return err
+	}
+	return errors.New("no init system found to restart " + daemon.Name + " daemon")
+}
+
+// reloadCron restarts cron daemon after store changes if required by restart policy.
+// returns error for cron.d files on busybox crond which does not read them.
+func reloadCron(store CronStore, runner Runner) error {
+	if staging() || cronRestart == CronRestartNever {
+		return nil
+	}
+
+	// Crontab command signals daemon itself
+	if _, ok := store.(*userCrontab); ok && cronRestart == CronRestartAuto {
+		return nil
+	}
+
+	// Busybox crond does not read cron.d files and does not watch crontab files
+	daemon, err := detectCronDaemon(runner)
+	if file, ok := store.(*cronDFile); ok && err == nil && daemon.Name == "busybox" {
+		return fmt.Errorf("unsupported cron store %s for busybox crond, use user crontab", file.path())
+	}
+	if cronRestart == CronRestartAlways || (err == nil && daemon.Name == "busybox") {
+		return restartCron(runner)
+	}
+	return nil
+}
+
+// busyboxCrond checks if busybox binary has crond applet.
+func busyboxCrond(runner Runner, path string) bool {
+	result, err := run(runner, "", path, "--list")
+	if err != nil {
+		return false
+	}
+	for _, applet := range strings.Fields(result.Stdout) {
+		if applet == "crond" {
+			return true
+		}
+	}
+	return false
+}
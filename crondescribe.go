@@ -0,0 +1,280 @@
+package gounix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CronLocale phrases used to describe cron schedules.
+// phrases with %s or %d placeholders formatted by fmt.
+type CronLocale struct {
+	Reboot         string // At system startup
+	EveryMinute    string // Every minute
+	EveryMinutes   string // Every %d minutes
+	At             string // At %s (times of day)
+	AtMinutes      string // At minute %s
+	PastEveryHour  string // past every hour
+	PastEveryHours string // past every %d hours
+	PastHours      string // past hour %s
+	Between        string // between %s and %s
+	DuringHours    string // during hour %s
+	EveryHours     string // every %d hours
+	OnWeekdays     string // on weekdays (all days except weekend)
+	EveryWeekday   string // every %s (single weekday)
+	OnWeekday      string // on %s (weekdays list)
+	OnDays         string // on day %s of the month
+	InMonths       string // in %s
+	Or             string // or
+	And            string // and
+	Range          string // %s to %s
+	Weekdays       [7]string
+	Months         [12]string
+}
+
+// EnglishLocale english cron description phrases.
+var EnglishLocale = CronLocale{
+	Reboot:         "At system startup",
+	EveryMinute:    "Every minute",
+	EveryMinutes:   "Every %d minutes",
+	At:             "At %s",
+	AtMinutes:      "At minute %s",
+	PastEveryHour:  "past every hour",
+	PastEveryHours: "past every %d hours",
+	PastHours:      "past hour %s",
+	Between:        "between %s and %s",
+	DuringHours:    "during hour %s",
+	EveryHours:     "every %d hours",
+	OnWeekdays:     "on weekdays",
+	EveryWeekday:   "every %s",
+	OnWeekday:      "on %s",
+	OnDays:         "on day %s of the month",
+	InMonths:       "in %s",
+	Or:             "or",
+	And:            "and",
+	Range:          "%s to %s",
+	Weekdays:       [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	Months:         [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+}
+
+// PersianLocale persian cron description phrases.
+var PersianLocale = CronLocale{
+	Reboot:         "هنگام راه‌اندازی سیستم",
+	EveryMinute:    "هر دقیقه",
+	EveryMinutes:   "هر %d دقیقه",
+	At:             "ساعت %s",
+	AtMinutes:      "دقیقه %s",
+	PastEveryHour:  "هر ساعت",
+	PastEveryHours: "هر %d ساعت",
+	PastHours:      "ساعت %s",
+	Between:        "بین %s و %s",
+	DuringHours:    "در ساعت %s",
+	EveryHours:     "هر %d ساعت",
+	OnWeekdays:     "در روزهای کاری",
+	EveryWeekday:   "هر %s",
+	OnWeekday:      "روزهای %s",
+	OnDays:         "روز %s ماه",
+	InMonths:       "در %s",
+	Or:             "یا",
+	And:            "و",
+	Range:          "%s تا %s",
+	Weekdays:       [7]string{"یکشنبه", "دوشنبه", "سه‌شنبه", "چهارشنبه", "پنجشنبه", "جمعه", "شنبه"},
+	Months:         [12]string{"ژانویه", "فوریه", "مارس", "آوریل", "مه", "ژوئن", "ژوئیه", "اوت", "سپتامبر", "اکتبر", "نوامبر", "دسامبر"},
+}
+
+// describer cron schedule describer.
+type describer struct {
+	locale *CronLocale
+}
+
+// join joins items with separator and locale and.
+func (d describer) join(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + d.locale.And + " " + items[len(items)-1]
+}
+
+// values describes set values as list with ranges (e.g. 1 to 5 and 9).
+func (d describer) values(f cronField, set cronSet, name func(int) string) string {
+	max := f.max
+	if f.wrap {
+		max--
+	}
+
+	items := make([]string, 0)
+	for v := f.min; v <= max; v++ {
+		if !set.has(v) {
+			continue
+		}
+		end := v
+		for end+1 <= max && set.has(end+1) {
+			end++
+		}
+		if end-v >= 2 {
+			items = append(items, fmt.Sprintf(d.locale.Range, name(v), name(end)))
+		} else {
+			for i := v; i <= end; i++ {
+				items = append(items, name(i))
+			}
+		}
+		v = end
+	}
+	return d.join(items)
+}
+
+// step get step of */step expression.
+func (d describer) step(expr string) int {
+	if step, ok := strings.CutPrefix(expr, "*/"); ok {
+		n, _ := strconv.Atoi(step)
+		return n
+	}
+	return 0
+}
+
+// times describes minutes and hours of schedule.
+func (d describer) times(minuteExpr, hourExpr string, minutes, hours cronSet) string {
+	number := strconv.Itoa
+	clock := func(h, m int) string { return fmt.Sprintf("%02d:%02d", h, m) }
+	minuteFull := minuteField.full(minutes)
+	hourFull := hourField.full(hours)
+
+	// Hours clause of repeated minutes
+	during := func(phrase string) string {
+		if hourFull {
+			return phrase
+		} else if step := d.step(hourExpr); step > 0 {
+			return phrase + ", " + fmt.Sprintf(d.locale.EveryHours, step)
+		}
+		first, last := -1, -1
+		for h := 0; h < 24; h++ {
+			if hours.has(h) {
+				if first < 0 {
+					first = h
+				}
+				last = h
+			}
+		}
+		if bitCount(hours) == last-first+1 {
+			return phrase + " " + fmt.Sprintf(d.locale.Between, clock(first, 0), clock(last, 59))
+		}
+		return phrase + " " + fmt.Sprintf(d.locale.DuringHours, d.values(hourField, hours, number))
+	}
+
+	switch {
+	case minuteFull:
+		return during(d.locale.EveryMinute)
+	case d.step(minuteExpr) > 0:
+		return during(fmt.Sprintf(d.locale.EveryMinutes, d.step(minuteExpr)))
+	case !hourFull && d.step(hourExpr) == 0 && bitCount(minutes)*bitCount(hours) <= 4:
+		times := make([]string, 0)
+		for h := 0; h < 24; h++ {
+			for m := 0; m < 60; m++ {
+				if hours.has(h) && minutes.has(m) {
+					times = append(times, clock(h, m))
+				}
+			}
+		}
+		return fmt.Sprintf(d.locale.At, d.join(times))
+	}
+
+	result := fmt.Sprintf(d.locale.AtMinutes, d.values(minuteField, minutes, number))
+	if hourFull {
+		return result + " " + d.locale.PastEveryHour
+	} else if step := d.step(hourExpr); step > 0 {
+		return result + " " + fmt.Sprintf(d.locale.PastEveryHours, step)
+	}
+	return result + " " + fmt.Sprintf(d.locale.PastHours, d.values(hourField, hours, number))
+}
+
+// days describes days of week, days of month and months of schedule.
+// day and weekday expressions select and/or joining like cron daemon.
+func (d describer) days(dayExpr, weekdayExpr string, days, months, weekdays cronSet, weekend Weekday) []string {
+	result := make([]string, 0)
+
+	// Day of week and day of month matched with or, with and if either starts with *
+	join := d.locale.Or
+	if strings.HasPrefix(dayExpr, "*") || strings.HasPrefix(weekdayExpr, "*") {
+		join = d.locale.And
+	}
+	dayParts := make([]string, 0)
+	if !weekdayField.full(weekdays) {
+		workdays := weekdayField.full(weekdays | 1<<uint(weekend.Real()))
+		workdays = workdays && !weekdays.has(weekend.Real())
+		westernWorkdays := weekdays == 0b0111110 && (weekend == Sunday || weekend == Saturday)
+		if workdays || westernWorkdays {
+			dayParts = append(dayParts, d.locale.OnWeekdays)
+		} else if bitCount(weekdays) == 1 {
+			for wd := 0; wd < 7; wd++ {
+				if weekdays.has(wd) {
+					dayParts = append(dayParts, fmt.Sprintf(d.locale.EveryWeekday, d.locale.Weekdays[wd]))
+				}
+			}
+		} else {
+			name := func(v int) string { return d.locale.Weekdays[v] }
+			dayParts = append(dayParts, fmt.Sprintf(d.locale.OnWeekday, d.values(weekdayField, weekdays, name)))
+		}
+	}
+	if !dayField.full(days) {
+		dayParts = append(dayParts, fmt.Sprintf(d.locale.OnDays, d.values(dayField, days, strconv.Itoa)))
+	}
+	if len(dayParts) > 0 {
+		result = append(result, strings.Join(dayParts, " "+join+" "))
+	}
+
+	if !monthField.full(months) {
+		name := func(v int) string { return d.locale.Months[v-1] }
+		result = append(result, fmt.Sprintf(d.locale.InMonths, d.values(monthField, months, name)))
+	}
+	return result
+}
+
+// tzLabel get timezone label of cron job. location name or fixed offset
+// relative to daemon local time (e.g. Asia/Tehran, local+03:30).
+func (c *cronDriver) tzLabel() string {
+	if c.tz == nil {
+		return ""
+	} else if c.tz.location != nil {
+		return c.tz.location.String()
+	}
+
+	offset := c.tz.hour*60 + c.tz.minute
+	if offset == 0 {
+		return ""
+	}
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	return fmt.Sprintf("local%s%02d:%02d", sign, offset/60, offset%60)
+}
+
+func (c *cronDriver) Describe() string {
+	return c.DescribeIn(&EnglishLocale)
+}
+
+func (c *cronDriver) DescribeIn(locale *CronLocale) string {
+	d := describer{locale: locale}
+	parts := make([]string, 0)
+	if c.reboot {
+		parts = append(parts, locale.Reboot)
+	} else {
+		// Describe splayed schedule in job timezone
+		fields := [5]string{c.minute, c.hour, c.day, c.month, c.weekday}
+		if shifted, ok := shiftCron(fields, c.splayOffset()); ok {
+			fields = shifted
+		}
+		schedule, err := newCronSchedule(strings.Join(fields[:], " "))
+		if err != nil {
+			return strings.Join(fields[:], " ")
+		}
+
+		parts = append(parts, d.times(fields[0], fields[1], schedule.minute, schedule.hour))
+		parts = append(parts, d.days(fields[2], fields[4], schedule.day, schedule.month, schedule.weekday, c.weekend())...)
+	}
+
+	if label := c.tzLabel(); label != "" {
+		parts = append(parts, "("+label+")")
+	}
+	return strings.Join(parts, " ")
+}
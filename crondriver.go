@@ -1,15 +1,34 @@
 package gounix
 
 import (
-	"os/exec"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
+// maxCronCommand maximum command length read by cron daemons (vixie cron MAX_COMMAND).
+const maxCronCommand = 999
+
 type cronDriver struct {
+	id      string
+	tags    []string
 	command string
+	raw     string // command text of parsed crontab line
+	user    string
+	store   CronStore
+	runner  Runner
 	tz      *CronTZ
+	wrapper cronWrapper
+	envs    [][2]string // crontab environment block
+	splay   time.Duration
+	exclude *CronCalendar
+	seed    string // splay seed (default hostname)
 
 	reboot  bool
 	minute  string
@@ -17,6 +36,7 @@ type cronDriver struct {
 	day     string
 	month   string
 	weekday string
+	invalid map[string]error // invalid setters input by field name
 }
 
 // .---------------- minute (0 - 59)
@@ -27,6 +47,7 @@ type cronDriver struct {
 // |  |  |  |  |
 // m h dom mon dow command
 func (c *cronDriver) set(minute, hour, day, mon, wd string) CronJob {
+	c.invalid = nil
 	c.minute = minute
 	c.hour = hour
 	c.day = day
@@ -35,20 +56,174 @@ func (c *cronDriver) set(minute, hour, day, mon, wd string) CronJob {
 	return c
 }
 
-// tzHour get time zone hour interval.
-func (c *cronDriver) tzHour() time.Duration {
-	if c.tz != nil {
-		return time.Duration(c.tz.hour) * time.Hour
+// apply sets field expression or records invalid input error.
+// valid input clears previous error of field.
+func (c *cronDriver) apply(f cronField, field *string, expr string, err error) CronJob {
+	if err != nil {
+		if c.invalid == nil {
+			c.invalid = make(map[string]error)
+		}
+		c.invalid[f.name] = err
+	} else {
+		delete(c.invalid, f.name)
+		*field = expr
 	}
-	return 0
+	return c
+}
+
+// tzOffset get time zone offset in minutes.
+func (c *cronDriver) tzOffset() int {
+	return c.tz.offset()
+}
+
+// splayOffset get stable splay offset in minutes within splay window
+// derived from seed or hostname.
+func (c *cronDriver) splayOffset() int {
+	window := int(c.splay / time.Minute)
+	if window < 1 {
+		return 0
+	}
+
+	seed := c.seed
+	if seed == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return 0
+		}
+		seed = host
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(seed))
+	return int(hash.Sum32() % uint32(window))
+}
+
+// shift get minutes schedule shifted on compile.
+// splay applied before time zone translation.
+func (c *cronDriver) shift() int {
+	return c.splayOffset() - c.tzOffset()
 }
 
-// tzMinute get time zone minute interval.
-func (c *cronDriver) tzMinute() time.Duration {
-	if c.tz != nil {
-		return time.Duration(c.tz.minute) * time.Minute
+// jobTime get time in job timezone, calendar dates evaluated in.
+func (c *cronDriver) jobTime(t time.Time) time.Time {
+	if c.tz != nil && c.tz.location != nil {
+		return t.In(c.tz.location)
+	} else if c.tz != nil {
+		local := t.In(time.Local)
+		return local.Add(time.Duration(c.tz.offset()) * time.Minute)
 	}
-	return 0
+	return t.In(time.Local)
+}
+
+// excluded checks if run time excluded by calendar.
+func (c *cronDriver) excluded(t time.Time) bool {
+	return c.exclude != nil && c.exclude.Excluded(c.jobTime(t))
+}
+
+// location get timezone which compiled schedule evaluated in.
+func (c *cronDriver) location() *time.Location {
+	if c.tz.native() {
+		return c.tz.location
+	}
+	return time.Local
+}
+
+// crontab get crontab storage backend.
+// default is crontab of job user. cron.d files use runner of job.
+func (c *cronDriver) crontab() CronStore {
+	if file, ok := c.store.(*cronDFile); ok && file.runner == nil {
+		return &cronDFile{name: file.name, runner: c.runner}
+	} else if c.store != nil {
+		return c.store
+	}
+	return &userCrontab{user: c.user, runner: c.runner}
+}
+
+// lines get crontab lines of cron job.
+// managed jobs and jobs wrapped with environment block (CRON_TZ line of
+// native timezone first) prefixed with marker comment. reset lines of
+// environment block written without value and restored by crontab.
+func (c *cronDriver) lines(system bool) ([]string, error) {
+	envs := c.envs
+	if c.tz.native() {
+		envs = append([][2]string{{"CRON_TZ", c.tz.location.String()}}, envs...)
+	}
+
+	// Wrap job with environment block
+	keys := make([]string, 0, len(envs))
+	block := make([]string, 0, len(envs))
+	resets := make([]string, 0, len(envs))
+	for _, env := range envs {
+		if !isEnvName(env[0]) {
+			return nil, fmt.Errorf("invalid cron job environment name %q", env[0])
+		} else if strings.ContainsAny(env[1], "\r\n") {
+			return nil, fmt.Errorf("invalid cron job environment value %q of %s", env[1], env[0])
+		}
+		if !slices.Contains(keys, env[0]) {
+			keys = append(keys, env[0])
+			block = append(block, env[0]+"="+quoteEnv(env[1]))
+			resets = append(resets, env[0]+"=")
+		}
+	}
+
+	// Marker comment
+	attrs := make([]string, 0, 3)
+	if c.id != "" {
+		attrs = append(attrs, "id="+c.id)
+		if len(c.tags) > 0 {
+			attrs = append(attrs, "tags="+strings.Join(c.tags, ","))
+		}
+	}
+	if len(keys) > 0 {
+		attrs = append(attrs, "env="+strings.Join(keys, ","))
+	}
+	result := make([]string, 0, len(block)+len(resets)+2)
+	if len(attrs) > 0 {
+		result = append(result, markerPrefix+strings.Join(attrs, " "))
+	}
+
+	line, err := c.compile(system)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, block...)
+	result = append(result, line)
+	return append(result, resets...), nil
+}
+
+// crontabCommand get command as written to crontab. unmodified parsed
+// commands keep raw text, so unescaped % (stdin of cron) preserved.
+func (c *cronDriver) crontabCommand() string {
+	if c.raw != "" && unescapeCommand(c.raw) == c.wrapped() {
+		return c.raw
+	}
+	return escapeCommand(c.wrapped())
+}
+
+// entry get command of crontab line with user column for system crontab.
+func (c *cronDriver) entry(system bool) string {
+	command := c.crontabCommand()
+	if system {
+		user := c.user
+		if user == "" {
+			user = "root"
+		}
+		command = user + " " + command
+	}
+	return command
+}
+
+// compile compiles cron job with user column for system crontab.
+// returns *CronScheduleWarning if schedule can not be translated.
+func (c *cronDriver) compile(system bool) (string, error) {
+	command := c.entry(system)
+	if c.reboot {
+		return "@reboot " + command, nil
+	}
+	interval, err := c.interval()
+	if err != nil {
+		return "", err
+	}
+	return interval + " " + command, nil
 }
 
 // weekend get time zone weekend.
@@ -59,36 +234,38 @@ func (c *cronDriver) weekend() Weekday {
 	return Sunday
 }
 
-// interval calculate cron expression
-// hour and minute based of timezone.
-func (c *cronDriver) interval() string {
-	def := c.minute + " " +
-		c.hour + " " +
-		c.day + " " +
-		c.month + " " +
-		c.weekday
+// interval calculate cron expression based of timezone.
+// day of week, day of month and month shifted when
+// timezone offset moves time across midnight. returns
+// *CronScheduleWarning if schedule can not be translated.
+func (c *cronDriver) interval() (string, error) {
+	fields := [5]string{c.minute, c.hour, c.day, c.month, c.weekday}
+	shifted, ok := shiftCron(fields, c.shift())
+	if !ok {
+		return "", c.untranslatable(fields)
+	}
+	return strings.Join(shifted[:], " "), nil
+}
 
-	// Return default interval if minute or hour not specified
-	if c.minute == "*" || strings.Contains(c.minute, "*/") ||
-		c.hour == "*" || strings.Contains(c.hour, "*/") {
-		return def
+// untranslatable get warning of schedule can not be shifted.
+func (c *cronDriver) untranslatable(fields [5]string) error {
+	local := strings.Join(fields[:], " ")
+	if c.splayOffset() != 0 {
+		return &CronScheduleWarning{Schedule: local, Reason: "can not be splayed and translated to daemon timezone"}
 	}
+	return &CronScheduleWarning{Schedule: local, Reason: "can not be translated to daemon timezone"}
+}
 
-	// Parse cron time
-	tz, err := time.Parse("15:4", c.hour+":"+c.minute)
+// schedule parse compiled cron expression.
+func (c *cronDriver) schedule() (*cronSchedule, error) {
+	if c.reboot {
+		return nil, ErrNoRunTime
+	}
+	interval, err := c.interval()
 	if err != nil {
-		return def
+		return nil, err
 	}
-
-	// Calculate the time in the specified timezone
-	duration := -(c.tzHour() + c.tzMinute())
-	timeInTz := tz.Add(duration)
-
-	return timeInTz.Format("4 ") +
-		timeInTz.Format("15 ") +
-		c.day + " " +
-		c.month + " " +
-		c.weekday
+	return newCronSchedule(interval)
 }
 
 func (c *cronDriver) AtReboot() CronJob {
@@ -117,48 +294,128 @@ func (c *cronDriver) Daily() CronJob {
 }
 
 func (c *cronDriver) EveryXHours(hours int) CronJob {
-	c.hour = "*/" + strconv.Itoa(hours)
-	return c
+	expr, err := hourField.every(hours)
+	return c.apply(hourField, &c.hour, expr, err)
 }
 
 func (c *cronDriver) EveryXMinutes(minutes int) CronJob {
-	c.minute = "*/" + strconv.Itoa(minutes)
-	return c
+	expr, err := minuteField.every(minutes)
+	return c.apply(minuteField, &c.minute, expr, err)
 }
 
 func (c *cronDriver) SetMinute(minute int) CronJob {
-	if minute >= 0 && minute <= 59 {
-		c.minute = strconv.Itoa(minute)
-	}
-	return c
+	expr, err := minuteField.single(minute)
+	return c.apply(minuteField, &c.minute, expr, err)
 }
 
 func (c *cronDriver) SetHour(hour int) CronJob {
-	if hour >= 0 && hour <= 23 {
-		c.hour = strconv.Itoa(hour)
-	}
-	return c
+	expr, err := hourField.single(hour)
+	return c.apply(hourField, &c.hour, expr, err)
 }
 
 func (c *cronDriver) SetDayOfMonth(day int) CronJob {
-	if day >= 1 && day <= 31 {
-		c.day = strconv.Itoa(day)
-	}
-	return c
+	expr, err := dayField.single(day)
+	return c.apply(dayField, &c.day, expr, err)
 }
 
 func (c *cronDriver) SetMonth(month int) CronJob {
-	if month >= 1 && month <= 12 {
-		c.month = strconv.Itoa(month)
-	}
-	return c
+	expr, err := monthField.single(month)
+	return c.apply(monthField, &c.month, expr, err)
 }
 
 func (c *cronDriver) SetDayOfWeek(day Weekday) CronJob {
-	if day.IsValid() {
-		c.weekday = strconv.Itoa(day.Real())
+	if !day.IsValid() {
+		return c.apply(weekdayField, &c.weekday, "", weekdayField.error(strconv.Itoa(int(day)), "invalid weekday"))
 	}
-	return c
+	return c.apply(weekdayField, &c.weekday, strconv.Itoa(day.Real()), nil)
+}
+
+func (c *cronDriver) Minutes(minutes ...int) CronJob {
+	expr, err := minuteField.list(minutes)
+	return c.apply(minuteField, &c.minute, expr, err)
+}
+
+func (c *cronDriver) Hours(hours ...int) CronJob {
+	expr, err := hourField.list(hours)
+	return c.apply(hourField, &c.hour, expr, err)
+}
+
+func (c *cronDriver) Days(days ...int) CronJob {
+	expr, err := dayField.list(days)
+	return c.apply(dayField, &c.day, expr, err)
+}
+
+func (c *cronDriver) Months(months ...int) CronJob {
+	expr, err := monthField.list(months)
+	return c.apply(monthField, &c.month, expr, err)
+}
+
+func (c *cronDriver) Weekdays(days ...Weekday) CronJob {
+	values := make([]int, 0, len(days))
+	for _, day := range days {
+		if day == Auto {
+			day = c.weekend()
+		} else if !day.IsValid() {
+			return c.apply(weekdayField, &c.weekday, "", weekdayField.error(strconv.Itoa(int(day)), "invalid weekday"))
+		}
+		values = append(values, day.Real())
+	}
+	expr, err := weekdayField.list(values)
+	return c.apply(weekdayField, &c.weekday, expr, err)
+}
+
+func (c *cronDriver) MinuteRange(from, to int) CronJob {
+	return c.MinuteRangeStep(from, to, 1)
+}
+
+func (c *cronDriver) HourRange(from, to int) CronJob {
+	return c.HourRangeStep(from, to, 1)
+}
+
+func (c *cronDriver) DayRange(from, to int) CronJob {
+	return c.DayRangeStep(from, to, 1)
+}
+
+func (c *cronDriver) MonthRange(from, to int) CronJob {
+	return c.MonthRangeStep(from, to, 1)
+}
+
+func (c *cronDriver) WeekdayRange(from, to Weekday) CronJob {
+	if !from.IsValid() || !to.IsValid() {
+		value := strconv.Itoa(int(from)) + "-" + strconv.Itoa(int(to))
+		return c.apply(weekdayField, &c.weekday, "", weekdayField.error(value, "invalid weekday"))
+	}
+
+	// Wrap around end of week (e.g. Friday to Monday)
+	values := make([]int, 0, 7)
+	for day := from.Real(); ; day = (day + 1) % 7 {
+		values = append(values, day)
+		if day == to.Real() {
+			break
+		}
+	}
+	expr, err := weekdayField.list(values)
+	return c.apply(weekdayField, &c.weekday, expr, err)
+}
+
+func (c *cronDriver) MinuteRangeStep(from, to, step int) CronJob {
+	expr, err := minuteField.span(from, to, step)
+	return c.apply(minuteField, &c.minute, expr, err)
+}
+
+func (c *cronDriver) HourRangeStep(from, to, step int) CronJob {
+	expr, err := hourField.span(from, to, step)
+	return c.apply(hourField, &c.hour, expr, err)
+}
+
+func (c *cronDriver) DayRangeStep(from, to, step int) CronJob {
+	expr, err := dayField.span(from, to, step)
+	return c.apply(dayField, &c.day, expr, err)
+}
+
+func (c *cronDriver) MonthRangeStep(from, to, step int) CronJob {
+	expr, err := monthField.span(from, to, step)
+	return c.apply(monthField, &c.month, expr, err)
 }
 
 func (c *cronDriver) Command(command string) CronJob {
@@ -166,73 +423,312 @@ func (c *cronDriver) Command(command string) CronJob {
 	return c
 }
 
+func (c *cronDriver) CrontabEnv(key, value string) CronJob {
+	c.envs = setEnvs(c.envs, key, value)
+	return c
+}
+
+func (c *cronDriver) Exclude(calendar *CronCalendar) CronJob {
+	c.exclude = calendar
+	return c
+}
+
+func (c *cronDriver) Splay(window time.Duration, seed string) CronJob {
+	c.splay = window
+	c.seed = seed
+	return c
+}
+
+func (c *cronDriver) ToSystemdTimer(name string) SystemdTimer {
+	return NewSystemdTimer(name, c)
+}
+
+func (c *cronDriver) ToAnacron(id string) AnacronJob {
+	return NewAnacronJob(id, c)
+}
+
+func (c *cronDriver) Tags(tags ...string) CronJob {
+	c.tags = append(c.tags, tags...)
+	return c
+}
+
+func (c *cronDriver) User(user string) CronJob {
+	c.user = user
+	return c
+}
+
+func (c *cronDriver) Runner(runner Runner) CronJob {
+	c.runner = runner
+	return c
+}
+
+func (c *cronDriver) Store(store CronStore) CronJob {
+	c.store = store
+	return c
+}
+
+func (c *cronDriver) ID(id string) CronJob {
+	c.id = id
+	return c
+}
+
 func (c *cronDriver) Compile() string {
+	system := c.crontab().System()
+	if line, err := c.compile(system); err == nil {
+		return line
+	}
+	return strings.Join([]string{c.minute, c.hour, c.day, c.month, c.weekday, c.entry(system)}, " ")
+}
+
+// next get next run time after specified time not excluded by calendar.
+func (c *cronDriver) next(schedule *cronSchedule, after time.Time) (time.Time, bool) {
+	limit := after.Add(cronSearchLimit)
+	for after.Before(limit) {
+		next, ok := schedule.next(after)
+		if !ok || !c.excluded(next) {
+			return next, ok
+		}
+		after = next
+	}
+	return time.Time{}, false
+}
+
+// prev get last run time before specified time not excluded by calendar.
+func (c *cronDriver) prev(schedule *cronSchedule, before time.Time) (time.Time, bool) {
+	limit := before.Add(-cronSearchLimit)
+	for before.After(limit) {
+		prev, ok := schedule.prev(before)
+		if !ok || !c.excluded(prev) {
+			return prev, ok
+		}
+		before = prev
+	}
+	return time.Time{}, false
+}
+
+// zone get location of job timezone at instant. fixed offsets
+// relative to daemon (system local) timezone.
+func (c *cronDriver) zone(at time.Time) *time.Location {
+	if c.tz != nil && c.tz.location != nil {
+		return c.tz.location
+	} else if offset := c.tz.offset(); offset != 0 {
+		_, local := at.In(time.Local).Zone()
+		return time.FixedZone("", local+offset*60)
+	}
+	return time.Local
+}
+
+// localNext get next run time of untranslated schedule evaluated in
+// job timezone, used by in-process scheduler.
+func (c *cronDriver) localNext(after time.Time) (time.Time, error) {
 	if c.reboot {
-		return "@reboot " + c.command
-	} else {
-		return c.interval() + " " + c.command
+		return time.Time{}, ErrNoRunTime
+	}
+	fields := [5]string{c.minute, c.hour, c.day, c.month, c.weekday}
+	shifted, ok := shiftCron(fields, c.splayOffset())
+	if !ok {
+		return time.Time{}, c.untranslatable(fields)
+	}
+	schedule, err := newCronSchedule(strings.Join(shifted[:], " "))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if next, ok := c.next(schedule, after.In(c.zone(after))); ok {
+		return next, nil
 	}
+	return time.Time{}, ErrNoRunTime
 }
 
-func (c *cronDriver) Exists() (bool, error) {
-	// Read cron jobs
-	lines, err := allCrons()
+func (c *cronDriver) NextRun(after time.Time) (time.Time, error) {
+	schedule, err := c.schedule()
 	if err != nil {
-		return false, err
+		return time.Time{}, err
+	}
+
+	if next, ok := c.next(schedule, after.In(c.location())); ok {
+		return next, nil
 	}
+	return time.Time{}, ErrNoRunTime
+}
 
-	// Search command in cron jobs
-	for _, line := range lines {
-		ok, cmd := parseCommand(line)
-		if ok && cmd == c.command {
-			return true, nil
+func (c *cronDriver) NextRuns(after time.Time, n int) ([]time.Time, error) {
+	schedule, err := c.schedule()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]time.Time, 0, n)
+	after = after.In(c.location())
+	for len(result) < n {
+		next, ok := c.next(schedule, after)
+		if !ok {
+			return nil, ErrNoRunTime
 		}
+		result = append(result, next)
+		after = next
+	}
+	return result, nil
+}
+
+func (c *cronDriver) PrevRun(before time.Time) (time.Time, error) {
+	schedule, err := c.schedule()
+	if err != nil {
+		return time.Time{}, err
 	}
 
-	// Not found
-	return false, nil
+	if prev, ok := c.prev(schedule, before.In(c.location())); ok {
+		return prev, nil
+	}
+	return time.Time{}, ErrNoRunTime
 }
 
-func (c *cronDriver) Install() (bool, error) {
-	var exists bool
-	var result strings.Builder
+func (c *cronDriver) Validate() error {
+	return c.validate(true)
+}
+
+// validate validates cron job. translate reports schedules that
+// can not be translated to daemon timezone.
+func (c *cronDriver) validate(translate bool) error {
+	return c.validateSchedule(c.entryErrors(), translate)
+}
 
+// entryErrors get errors of crontab entry (identity, user, command and environment).
+func (c *cronDriver) entryErrors() []error {
+	errs := make([]error, 0)
+	if strings.ContainsFunc(c.id, unicode.IsSpace) {
+		errs = append(errs, fmt.Errorf("invalid cron job id %q", c.id))
+	}
+	for _, tag := range c.tags {
+		if tag == "" || strings.ContainsFunc(tag, unicode.IsSpace) || strings.Contains(tag, ",") {
+			errs = append(errs, fmt.Errorf("invalid cron job tag %q", tag))
+		} else if c.id == "" {
+			errs = append(errs, fmt.Errorf("cron job tag %q requires id", tag))
+		}
+	}
+	if c.id == "" && (c.wrapper.enabled() || c.exclude != nil) {
+		errs = append(errs, errors.New("cron job wrapper and exclusion calendar require id"))
+	}
+	if strings.ContainsFunc(c.user, unicode.IsSpace) {
+		errs = append(errs, fmt.Errorf("invalid cron job user %q", c.user))
+	}
+	if strings.TrimSpace(c.command) == "" {
+		errs = append(errs, errors.New("empty cron job command"))
+	} else if strings.ContainsAny(c.wrapped(), "\r\n") {
+		errs = append(errs, fmt.Errorf("cron job command %q contains new line", c.wrapped()))
+	} else if c.raw != "" && c.crontabCommand() != c.raw && strings.Count(c.raw, "%") != strings.Count(c.raw, `\%`) {
+		errs = append(errs, fmt.Errorf("cron job command %q contains unescaped %% (stdin), can not be rewritten", c.raw))
+	} else if n := len(c.crontabCommand()); n > maxCronCommand && c.exclude != nil {
+		errs = append(errs, fmt.Errorf("cron job command of %d characters exceeds cron limit %d, reduce exclusion calendar dates", n, maxCronCommand))
+	} else if n > maxCronCommand {
+		errs = append(errs, fmt.Errorf("cron job command of %d characters exceeds cron limit %d", n, maxCronCommand))
+	}
+	for _, env := range c.envs {
+		if !isEnvName(env[0]) {
+			errs = append(errs, fmt.Errorf("invalid cron job environment name %q", env[0]))
+		} else if env[1] == "" || strings.ContainsAny(env[1], "\r\n") {
+			errs = append(errs, fmt.Errorf("invalid cron job environment value %q of %s", env[1], env[0]))
+		}
+	}
+	for _, env := range c.wrapper.env {
+		if !isEnvName(env[0]) {
+			errs = append(errs, fmt.Errorf("invalid cron job environment name %q", env[0]))
+		}
+	}
+	return errs
+}
+
+// validateSchedule validates timezone, calendar, splay and fields of cron
+// job schedule joined with prior errors. translate reports schedules that
+// can not be translated to daemon timezone.
+func (c *cronDriver) validateSchedule(errs []error, translate bool) error {
+	if c.tz != nil && c.tz.err != nil {
+		errs = append(errs, c.tz.err)
+	}
+	if c.exclude != nil && c.exclude.err != nil {
+		errs = append(errs, c.exclude.err)
+	}
+	if c.splay < 0 || c.splay > 24*time.Hour {
+		errs = append(errs, fmt.Errorf("cron job splay %s out of range 0-24h", c.splay))
+	}
+	if c.reboot {
+		return errors.Join(errs...)
+	}
+
+	// Invalid setters input and fields
+	fields := []string{c.minute, c.hour, c.day, c.month, c.weekday}
+	specs := []cronField{minuteField, hourField, dayField, monthField, weekdayField}
+	for i, spec := range specs {
+		if err, ok := c.invalid[spec.name]; ok {
+			errs = append(errs, err)
+		} else if _, err := spec.parse(fields[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// Impossible schedule
+	local := strings.Join(fields, " ")
+	schedule, err := newCronSchedule(local)
+	if err != nil {
+		return err
+	}
+	if _, ok := schedule.next(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)); !ok {
+		return &CronScheduleWarning{Schedule: local, Reason: "never fires"}
+	}
+
+	// Untranslatable schedule
+	if !translate {
+		return nil
+	}
+	if _, ok := shiftCron([5]string(fields), c.shift()); !ok {
+		return c.untranslatable([5]string(fields))
+	}
+	return nil
+}
+
+func (c *cronDriver) Exists() (bool, error) {
 	// Read cron jobs
-	lines, err := allCrons()
+	tab, err := c.crontab().Read()
 	if err != nil {
 		return false, err
 	}
 
-	// Try to find and update cron job
-	for _, line := range lines {
-		// Find and update
-		ok, cmd := parseCommand(line)
-		if ok && cmd == c.command {
-			exists = true
-			result.WriteString(c.Compile() + "\n")
-			continue
-		}
+	// Search id or command in cron jobs
+	return len(tab.find(c)) > 0, nil
+}
 
-		// Other valid commands
-		if strings.TrimSpace(line) != "" {
-			result.WriteString(line + "\n")
-		}
+func (c *cronDriver) Install() (bool, error) {
+	// Validate cron job
+	if err := c.Validate(); err != nil {
+		return false, err
 	}
 
-	// Append if not exists
-	if !exists {
-		result.WriteString(c.Compile() + "\n")
+	// Read cron jobs under lock
+	unlock, err := lockCrontab(c.crontab(), c.runner)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	tab, err := c.crontab().Read()
+	if err != nil {
+		return false, err
 	}
 
-	// Update cron jobs
-	cmd := `echo "` + result.String() + `" | crontab -`
-	err = cmdError(exec.Command("sudo", "bash", "-c", cmd).Run())
+	// Update or append cron job
+	_, err = tab.Set(c)
+	if err != nil {
+		return false, err
+	}
+	err = c.crontab().Write(tab)
 	if err != nil {
 		return false, err
 	}
 
-	// Restart cron service
-	err = cmdError(exec.Command("sudo", "systemctl", "restart", "cron").Run())
+	// Restart cron service if required
+	err = reloadCron(c.crontab(), c.runner)
 	if err != nil {
 		return false, err
 	}
@@ -241,35 +737,27 @@ func (c *cronDriver) Install() (bool, error) {
 }
 
 func (c *cronDriver) Uninstall() error {
-	var result strings.Builder
-
-	// Read cron jobs
-	lines, err := allCrons()
+	// Read cron jobs under lock
+	unlock, err := lockCrontab(c.crontab(), c.runner)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	tab, err := c.crontab().Read()
 	if err != nil {
 		return err
 	}
 
 	// Exclude cron from jobs list
-	for _, line := range lines {
-		// Find and exclude
-		ok, cmd := parseCommand(line)
-		if ok && cmd == c.command {
-			continue
-		}
-
-		// Other valid commands
-		if strings.TrimSpace(line) != "" {
-			result.WriteString(line + "\n")
-		}
+	_, err = tab.Remove(c)
+	if err != nil {
+		return err
 	}
-
-	// Update cron jobs
-	cmd := `echo "` + result.String() + `" | crontab -`
-	err = cmdError(exec.Command("sudo", "bash", "-c", cmd).Run())
+	err = c.crontab().Write(tab)
 	if err != nil {
 		return err
 	}
 
-	// Restart cron service
-	return cmdError(exec.Command("sudo", "systemctl", "restart", "cron").Run())
+	// Restart cron service if required
+	return reloadCron(c.crontab(), c.runner)
 }
@@ -0,0 +1,251 @@
+package gounix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// cronSet bit set of cron field values.
+type cronSet uint64
+
+func (s cronSet) has(v int) bool {
+	return v >= 0 && v < 64 && s&(1<<uint(v)) != 0
+}
+
+// cronField cron expression field specification.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string // names of values starting from min
+	wrap  bool     // max value is alias of min value (sunday as 7)
+}
+
+var (
+	minuteField  = cronField{name: "minute", min: 0, max: 59}
+	hourField    = cronField{name: "hour", min: 0, max: 23}
+	dayField     = cronField{name: "day of month", min: 1, max: 31}
+	monthField   = cronField{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	weekdayField = cronField{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}, wrap: true}
+)
+
+// error creates field error.
+func (f cronField) error(value, reason string) error {
+	return &CronFieldError{Field: f.name, Value: value, Reason: reason}
+}
+
+// bounds get field bounds description.
+func (f cronField) bounds() string {
+	return "out of range " + strconv.Itoa(f.min) + "-" + strconv.Itoa(f.max)
+}
+
+// value parse single field value or name.
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, f.error(s, "not a number or name")
+	} else if v < f.min || v > f.max {
+		return 0, f.error(s, f.bounds())
+	}
+	return v, nil
+}
+
+// parse parses field expression (lists, ranges, steps and names) into values set.
+func (f cronField) parse(expr string) (cronSet, error) {
+	var set cronSet
+	if expr == "" {
+		return 0, f.error(expr, "empty field")
+	}
+
+	for _, item := range strings.Split(expr, ",") {
+		// Parse step
+		rng, stepExpr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepExpr)
+			if err != nil || n < 1 || n > f.max {
+				return 0, f.error(expr, "invalid step "+strconv.Quote(stepExpr))
+			}
+			step = n
+		}
+
+		// Parse range
+		var from, to int
+		var err error
+		if rng == "*" {
+			from, to = f.min, f.max
+		} else if a, b, ok := strings.Cut(rng, "-"); ok {
+			if from, err = f.value(a); err != nil {
+				return 0, err
+			}
+			if to, err = f.value(b); err != nil {
+				return 0, err
+			}
+			if from > to {
+				return 0, f.error(expr, "invalid range "+strconv.Quote(rng))
+			}
+		} else {
+			if from, err = f.value(rng); err != nil {
+				return 0, err
+			}
+			to = from
+			if hasStep {
+				to = f.max
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	// Map alias value to real value
+	if f.wrap && set.has(f.max) {
+		set = set&^(1<<uint(f.max)) | 1<<uint(f.min)
+	}
+	return set, nil
+}
+
+// single formats single value into field expression.
+func (f cronField) single(v int) (string, error) {
+	if v < f.min || v > f.max {
+		return "", f.error(strconv.Itoa(v), f.bounds())
+	}
+	return strconv.Itoa(v), nil
+}
+
+// every formats */step expression.
+func (f cronField) every(step int) (string, error) {
+	if step < 1 || step > f.max {
+		return "", f.error("*/"+strconv.Itoa(step), "step out of range 1-"+strconv.Itoa(f.max))
+	}
+	return "*/" + strconv.Itoa(step), nil
+}
+
+// list formats values list into field expression.
+func (f cronField) list(values []int) (string, error) {
+	var set cronSet
+	for _, v := range values {
+		if v < f.min || v > f.max {
+			return "", f.error(strconv.Itoa(v), f.bounds())
+		}
+		set |= 1 << uint(v)
+	}
+	if set == 0 {
+		return "", f.error("", "empty list")
+	}
+
+	// Map alias value to real value
+	if f.wrap && set.has(f.max) {
+		set = set&^(1<<uint(f.max)) | 1<<uint(f.min)
+	}
+	return f.format(set), nil
+}
+
+// span formats range with step into field expression (step 1 means no step).
+func (f cronField) span(from, to, step int) (string, error) {
+	expr := strconv.Itoa(from) + "-" + strconv.Itoa(to)
+	if step > 1 {
+		expr += "/" + strconv.Itoa(step)
+	}
+
+	if from < f.min || to > f.max {
+		return "", f.error(expr, f.bounds())
+	} else if from > to {
+		return "", f.error(expr, "invalid range")
+	} else if step < 1 || step > f.max {
+		return "", f.error(expr, "step out of range 1-"+strconv.Itoa(f.max))
+	}
+	return expr, nil
+}
+
+// splitFields split n whitespace separated fields from line
+// and return the rest of line untouched.
+func splitFields(line string, n int) ([]string, string) {
+	fields := make([]string, 0, n)
+	rest := strings.TrimLeftFunc(line, unicode.IsSpace)
+	for len(fields) < n && rest != "" {
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+	return fields, strings.TrimRightFunc(rest, unicode.IsSpace)
+}
+
+// parseCronLine parses crontab job line into cron driver.
+// system crontab lines have user column after schedule.
+func parseCronLine(line string, system bool) (*cronDriver, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, fmt.Errorf("%q is not a cron job", line)
+	}
+
+	driver := new(cronDriver)
+	driver.set("*", "*", "*", "*", "*")
+
+	// Handle predefined aliases
+	if strings.HasPrefix(line, "@") {
+		fields, command := splitFields(line, 1)
+		if system {
+			fields, command = splitFields(line, 2)
+			if len(fields) == 2 {
+				driver.user = fields[1]
+			}
+		}
+		if command == "" {
+			return nil, fmt.Errorf("missing command in %q", line)
+		}
+		switch strings.ToLower(fields[0]) {
+		case "@reboot":
+			driver.reboot = true
+		case "@yearly", "@annually":
+			driver.set("0", "0", "1", "1", "*")
+		case "@monthly":
+			driver.set("0", "0", "1", "*", "*")
+		case "@weekly":
+			driver.set("0", "0", "*", "*", "0")
+		case "@daily", "@midnight":
+			driver.set("0", "0", "*", "*", "*")
+		case "@hourly":
+			driver.set("0", "*", "*", "*", "*")
+		default:
+			return nil, fmt.Errorf("unknown cron alias %q", fields[0])
+		}
+		driver.command = unescapeCommand(command)
+		driver.raw = command
+		return driver, nil
+	}
+
+	// Handle five fields expressions
+	fields, command := splitFields(line, 5)
+	if system {
+		fields, command = splitFields(line, 6)
+		if len(fields) == 6 {
+			driver.user = fields[5]
+		}
+	}
+	if len(fields) < 5 || command == "" {
+		return nil, fmt.Errorf("%q is not a cron job", line)
+	}
+	specs := []cronField{minuteField, hourField, dayField, monthField, weekdayField}
+	for i, spec := range specs {
+		if _, err := spec.parse(fields[i]); err != nil {
+			return nil, err
+		}
+	}
+	driver.set(fields[0], fields[1], fields[2], fields[3], fields[4])
+	driver.command = unescapeCommand(command)
+	driver.raw = command
+	return driver, nil
+}
@@ -0,0 +1,113 @@
+package gounix
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit maximum time range to search for cron run time.
+const cronSearchLimit = 30 * 366 * 24 * time.Hour
+
+// cronSchedule parsed cron expression for run time calculation.
+type cronSchedule struct {
+	minute  cronSet
+	hour    cronSet
+	day     cronSet
+	month   cronSet
+	weekday cronSet
+
+	dayStar     bool
+	weekdayStar bool
+}
+
+// newCronSchedule parses five fields cron expression.
+func newCronSchedule(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q", expr)
+	}
+
+	var err error
+	s := new(cronSchedule)
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.day, err = dayField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.weekday, err = weekdayField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// Day of month and day of week restricted together
+	// matches either one of them (vixie cron behavior)
+	s.dayStar = strings.HasPrefix(fields[2], "*")
+	s.weekdayStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// matchDay checks if day of time matches schedule.
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	day := s.day.has(t.Day())
+	weekday := s.weekday.has(int(t.Weekday()))
+	if s.dayStar || s.weekdayStar {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// next returns first run time after specified time.
+func (s *cronSchedule) next(after time.Time) (time.Time, bool) {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case !s.month.has(int(m)):
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		case !s.hour.has(t.Hour()):
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, loc)
+		case !s.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// prev returns last run time before specified time.
+func (s *cronSchedule) prev(before time.Time) (time.Time, bool) {
+	loc := before.Location()
+	t := before.Truncate(time.Minute)
+	if !t.Before(before) {
+		t = t.Add(-time.Minute)
+	}
+	limit := t.Add(-cronSearchLimit)
+	for t.After(limit) {
+		y, m, d := t.Date()
+		switch {
+		case !s.month.has(int(m)):
+			t = time.Date(y, m, 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !s.matchDay(t):
+			t = time.Date(y, m, d, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !s.hour.has(t.Hour()):
+			t = time.Date(y, m, d, t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		case !s.minute.has(t.Minute()):
+			t = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,253 @@
+package gounix
+
+import (
+	"strconv"
+	"strings"
+)
+
+const minutesOfDay = 24 * 60
+
+// monthDays minimum and maximum days of months (february varies on leap years).
+var monthDays = [13][2]int{{}, {31, 31}, {28, 29}, {31, 31}, {30, 30}, {31, 31}, {30, 30}, {31, 31}, {31, 31}, {30, 30}, {31, 31}, {30, 30}, {31, 31}}
+
+// full checks if set contains all values of field.
+func (f cronField) full(set cronSet) bool {
+	max := f.max
+	if f.wrap {
+		max--
+	}
+	for v := f.min; v <= max; v++ {
+		if !set.has(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// dayOf checks if field is day of month or day of week. cron matches
+// either day when both restricted and both when one starts with *.
+func (f cronField) dayOf() bool {
+	return f.name == dayField.name || f.name == weekdayField.name
+}
+
+// format formats values set into cron field expression. day fields
+// never formatted with * prefix to keep day matching semantic.
+func (f cronField) format(set cronSet) string {
+	max := f.max
+	if f.wrap {
+		max--
+	}
+
+	// Collect values
+	values := make([]int, 0)
+	for v := f.min; v <= max; v++ {
+		if set.has(v) {
+			values = append(values, v)
+		}
+	}
+	if len(values) == max-f.min+1 && !f.dayOf() {
+		return "*"
+	}
+
+	// Detect stepped range (*/step or from-to/step)
+	if len(values) > 2 && values[1]-values[0] > 1 {
+		step, stepped := values[1]-values[0], true
+		for i := 1; stepped && i < len(values); i++ {
+			stepped = values[i]-values[i-1] == step
+		}
+		first, last := values[0], values[len(values)-1]
+		if stepped && first == f.min && last+step > max && !f.dayOf() {
+			return "*/" + strconv.Itoa(step)
+		} else if stepped {
+			return strconv.Itoa(first) + "-" + strconv.Itoa(last) + "/" + strconv.Itoa(step)
+		}
+	}
+
+	// Join runs as ranges
+	parts := make([]string, 0)
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+		switch j - i {
+		case 0:
+			parts = append(parts, strconv.Itoa(values[i]))
+		case 1:
+			parts = append(parts, strconv.Itoa(values[i]), strconv.Itoa(values[j]))
+		default:
+			parts = append(parts, strconv.Itoa(values[i])+"-"+strconv.Itoa(values[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// shiftCron shifts five cron fields by specified minutes. day of week,
+// day of month and month are shifted when time crosses midnight.
+// returns false if shifted schedule can not be expressed as single expression.
+func shiftCron(fields [5]string, minutes int) ([5]string, bool) {
+	if minutes == 0 {
+		return fields, true
+	}
+
+	minuteSet, err := minuteField.parse(fields[0])
+	if err != nil {
+		return fields, false
+	}
+	hourSet, err := hourField.parse(fields[1])
+	if err != nil {
+		return fields, false
+	}
+	daySet, err := dayField.parse(fields[2])
+	if err != nil {
+		return fields, false
+	}
+	monthSet, err := monthField.parse(fields[3])
+	if err != nil {
+		return fields, false
+	}
+	weekdaySet, err := weekdayField.parse(fields[4])
+	if err != nil {
+		return fields, false
+	}
+
+	// Shift times of day
+	var times [minutesOfDay]bool
+	var hours, mins cronSet
+	count, delta, uniform := 0, 0, true
+	for h := 0; h < 24; h++ {
+		for m := 0; m < 60; m++ {
+			if !hourSet.has(h) || !minuteSet.has(m) {
+				continue
+			}
+			t := h*60 + m + minutes
+			d := floorDiv(t, minutesOfDay)
+			t -= d * minutesOfDay
+			if count == 0 {
+				delta = d
+			} else if d != delta {
+				uniform = false
+			}
+			if !times[t] {
+				times[t] = true
+				hours |= 1 << uint(t/60)
+				mins |= 1 << uint(t%60)
+				count++
+			}
+		}
+	}
+
+	// Shifted times must be hours × minutes product
+	if count == 0 || count != bitCount(hours)*bitCount(mins) {
+		return fields, false
+	}
+	result := fields
+	result[0] = minuteField.format(mins)
+	result[1] = hourField.format(hours)
+
+	// Days not restricted or not crossed midnight
+	dayFull := dayField.full(daySet)
+	monthFull := monthField.full(monthSet)
+	weekdayFull := weekdayField.full(weekdaySet)
+	if dayFull && monthFull && weekdayFull {
+		return result, true
+	}
+	if !uniform || delta < -1 || delta > 1 {
+		return fields, false
+	}
+	if delta == 0 {
+		return result, true
+	}
+
+	// Day fields matched together when either starts with *, shifted
+	// fields formatted without * so restricted starred field changes semantic
+	dayStar, weekdayStar := strings.HasPrefix(fields[2], "*"), strings.HasPrefix(fields[4], "*")
+	if (dayStar && !dayFull || weekdayStar && !weekdayFull) && !(dayStar && dayFull) && !(weekdayStar && weekdayFull) {
+		return fields, false
+	}
+
+	// Shift day of week
+	if !weekdayFull {
+		var shifted cronSet
+		for wd := 0; wd < 7; wd++ {
+			if weekdaySet.has(wd) {
+				shifted |= 1 << uint((wd+delta+7)%7)
+			}
+		}
+		result[4] = weekdayField.format(shifted)
+	}
+
+	// Shift day of month and month
+	if dayFull && !monthFull {
+		return fields, false
+	}
+	if !dayFull {
+		var days, months cronSet
+		pairs := make(map[[2]int]bool)
+		for mon := 1; mon <= 12; mon++ {
+			if !monthSet.has(mon) {
+				continue
+			}
+			for day := 1; day <= monthDays[mon][1]; day++ {
+				if !daySet.has(day) {
+					continue
+				}
+				m, d, ok := shiftDate(mon, day, delta)
+				if !ok {
+					return fields, false
+				}
+				pairs[[2]int{m, d}] = true
+				days |= 1 << uint(d)
+				months |= 1 << uint(m)
+			}
+		}
+		if len(pairs) == 0 || len(pairs) != bitCount(days)*bitCount(months) {
+			return fields, false
+		}
+		result[2] = dayField.format(days)
+		result[3] = monthField.format(months)
+	}
+	return result, true
+}
+
+// shiftDate shifts month day by one day forward or backward.
+// returns false if result is ambiguous on leap years (february 28
+// and leap day 29 forward, march 1st backward).
+func shiftDate(month, day, delta int) (int, int, bool) {
+	if delta > 0 {
+		if day < monthDays[month][0] {
+			return month, day + 1, true
+		} else if day == monthDays[month][1] && monthDays[month][0] == monthDays[month][1] {
+			return month%12 + 1, 1, true
+		}
+		return 0, 0, false
+	}
+
+	if day > 1 {
+		return month, day - 1, true
+	}
+	prev := (month+10)%12 + 1
+	if monthDays[prev][0] != monthDays[prev][1] {
+		return 0, 0, false
+	}
+	return prev, monthDays[prev][0], true
+}
+
+// floorDiv integer division rounded toward negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// bitCount count values of set.
+func bitCount(s cronSet) int {
+	n := 0
+	for ; s != 0; s &= s - 1 {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,28 @@
+package gounix
+
+// CronStore crontab storage backend.
+type CronStore interface {
+	// Read reads and parses crontab. missing crontab returns empty document.
+	Read() (*Crontab, error)
+	// Write replaces crontab with document.
+	Write(tab *Crontab) error
+	// System checks if crontab entries have user column (/etc/cron.d files).
+	System() bool
+}
+
+// NewUserCrontab creates user crontab store (crontab -u user).
+// empty user means crontab of root.
+func NewUserCrontab(user string) CronStore {
+	store := new(userCrontab)
+	store.user = user
+	return store
+}
+
+// NewCronDFile creates /etc/cron.d drop-in file store.
+// entries of file run as cron job user (default root).
+// file removed when no line left. name can contain letters, digits, _ and - only.
+func NewCronDFile(name string) CronStore {
+	store := new(cronDFile)
+	store.name = name
+	return store
+}
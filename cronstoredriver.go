@@ -0,0 +1,115 @@
+package gounix
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type userCrontab struct {
+	user   string
+	runner Runner
+}
+
+func (u *userCrontab) args(args ...string) []string {
+	if u.user != "" {
+		return append([]string{"crontab", "-u", u.user}, args...)
+	}
+	return append([]string{"crontab"}, args...)
+}
+
+// spool get crontab file of user in cron spool (staging filesystems).
+func (u *userCrontab) spool() string {
+	user := u.user
+	if user == "" {
+		user = "root"
+	}
+	if info, err := fileSystem.Stat("/var/spool/cron"); err == nil && info.IsDir() {
+		if info, err := fileSystem.Stat("/var/spool/cron/crontabs"); err != nil || !info.IsDir() {
+			return "/var/spool/cron/" + user
+		}
+	}
+	return "/var/spool/cron/crontabs/" + user
+}
+
+// parse parses crontab content of user.
+func (u *userCrontab) parse(content string) *Crontab {
+	tab := ParseCrontab(content)
+	tab.owner = u.user
+	if tab.owner == "" {
+		tab.owner = "root"
+	}
+	return tab
+}
+
+func (u *userCrontab) Read() (*Crontab, error) {
+	if staging() {
+		content, err := fileSystem.ReadFile(u.spool())
+		if os.IsNotExist(err) {
+			return u.parse(""), nil
+		} else if err != nil {
+			return nil, err
+		}
+		return u.parse(string(content)), nil
+	}
+
+	result, err := runRoot(u.runner, "", u.args("-l")...)
+	if result.ExitCode != 0 && strings.Contains(result.Stderr, "no crontab") {
+		return u.parse(""), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return u.parse(result.Stdout), nil
+}
+
+func (u *userCrontab) Write(tab *Crontab) error {
+	if staging() {
+		return fileSystem.WriteFile(u.spool(), []byte(tab.String()), 0600)
+	}
+	_, err := runRoot(u.runner, tab.String(), u.args("-")...)
+	return err
+}
+
+func (u *userCrontab) System() bool {
+	return false
+}
+
+type cronDFile struct {
+	name   string
+	runner Runner
+}
+
+func (c *cronDFile) path() string {
+	return "/etc/cron.d/" + c.name
+}
+
+func (c *cronDFile) Read() (*Crontab, error) {
+	content, err := readFile(c.runner, c.path())
+	if os.IsNotExist(err) {
+		return ParseSystemCrontab(""), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return ParseSystemCrontab(string(content)), nil
+}
+
+func (c *cronDFile) Write(tab *Crontab) error {
+	// Validate file name, cron ignores files with dots
+	if c.name == "" || strings.ContainsFunc(c.name, func(r rune) bool {
+		return r != '_' && r != '-' && !(r >= 'a' && r <= 'z') &&
+			!(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9')
+	}) {
+		return fmt.Errorf("invalid cron.d file name %q", c.name)
+	}
+
+	// Remove empty file
+	if strings.TrimSpace(tab.String()) == "" {
+		return removeFile(c.runner, c.path())
+	}
+
+	return writeFile(c.runner, c.path(), []byte(tab.String()), 0644)
+}
+
+func (c *cronDFile) System() bool {
+	return true
+}
@@ -0,0 +1,632 @@
+package gounix
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CrontabLineKind represents the kind of a crontab line.
+type CrontabLineKind int
+
+const (
+	CrontabBlank   CrontabLineKind = 0 // empty or whitespace only line
+	CrontabComment CrontabLineKind = 1 // # comment line
+	CrontabEnv     CrontabLineKind = 2 // environment assignment (e.g. MAILTO=root)
+	CrontabEntry   CrontabLineKind = 3 // cron job entry
+	CrontabUnknown CrontabLineKind = 4 // unparsable line, kept untouched
+)
+
+// CrontabLine represents a single parsed crontab line.
+type CrontabLine struct {
+	Kind  CrontabLineKind
+	Raw   string  // line text as written to crontab
+	Key   string  // environment name (CrontabEnv only)
+	Value string  // environment value (CrontabEnv only)
+	Job   CronJob // cron job (CrontabEntry only)
+}
+
+// Crontab represents a crontab document. untouched lines
+// serialized back byte-for-byte.
+type Crontab struct {
+	lines   []CrontabLine
+	newline bool   // content ends with new line
+	system  bool   // entries have user column
+	owner   string // crontab owner (user crontabs read from store)
+}
+
+// ParseCrontab parses user crontab content into document.
+func ParseCrontab(content string) *Crontab {
+	return parseCrontab(content, false)
+}
+
+// ParseSystemCrontab parses system crontab content (/etc/crontab
+// and /etc/cron.d files) with user column into document.
+func ParseSystemCrontab(content string) *Crontab {
+	return parseCrontab(content, true)
+}
+
+// ReadCrontab reads and parses root crontab.
+func ReadCrontab() (*Crontab, error) {
+	return NewUserCrontab("").Read()
+}
+
+// WriteCrontab replaces root crontab with document.
+// content piped to crontab stdin without shell interpretation.
+func WriteCrontab(tab *Crontab) error {
+	return NewUserCrontab("").Write(tab)
+}
+
+// Lines returns all lines of crontab.
+func (c *Crontab) Lines() []CrontabLine {
+	return append([]CrontabLine(nil), c.lines...)
+}
+
+// Jobs returns all cron jobs of crontab.
+func (c *Crontab) Jobs() []CronJob {
+	result := make([]CronJob, 0)
+	for _, line := range c.lines {
+		if line.Kind == CrontabEntry {
+			result = append(result, line.Job)
+		}
+	}
+	return result
+}
+
+// Entries returns all cron jobs of crontab with schedule, command and
+// management details. owner filled from user column of system crontab.
+func (c *Crontab) Entries() []CronEntry {
+	result := make([]CronEntry, 0)
+	for _, line := range c.lines {
+		if driver, ok := line.Job.(*cronDriver); ok && line.Kind == CrontabEntry {
+			schedule := "@reboot"
+			if !driver.reboot {
+				schedule = strings.Join([]string{driver.minute, driver.hour, driver.day, driver.month, driver.weekday}, " ")
+			}
+			command := driver.command
+			if driver.id != "" {
+				command = unwrapCommand(command)
+			}
+			result = append(result, CronEntry{
+				Job:      driver,
+				Schedule: schedule,
+				Command:  command,
+				Owner:    driver.user,
+				ID:       driver.id,
+				Tags:     driver.tags,
+				Managed:  driver.id != "",
+			})
+		}
+	}
+	return result
+}
+
+// Find finds first cron job by command.
+func (c *Crontab) Find(command string) (CronJob, bool) {
+	for _, line := range c.lines {
+		if line.Kind == CrontabEntry && cronCommand(line.Job) == command {
+			return line.Job, true
+		}
+	}
+	return nil, false
+}
+
+// FindID finds managed cron job by id.
+func (c *Crontab) FindID(id string) (CronJob, bool) {
+	for i, line := range c.lines {
+		if line.Kind == CrontabEntry && id != "" && c.marker(i) == id {
+			return line.Job, true
+		}
+	}
+	return nil, false
+}
+
+// Set updates cron job entries or appends job. jobs with id matched by
+// marker comment, unmanaged entries with the same command adopted (marked)
+// if no marked entry found. jobs without id matched by command.
+// returns false if job not exists and appended.
+// returns error if variables of environment block can not be restored after job.
+func (c *Crontab) Set(job CronJob) (bool, error) {
+	lines, err := c.jobLines(job)
+	if err != nil {
+		return false, err
+	}
+	saved, newline := slices.Clone(c.lines), c.newline
+	indexes := c.find(job)
+	if len(indexes) == 0 {
+		c.lines = append(c.lines, lines...)
+		c.newline = true
+	}
+
+	// Replace from last to keep indexes valid
+	for n := len(indexes) - 1; n >= 0; n-- {
+		from, to := c.span(indexes[n])
+		c.replace(from, to, lines...)
+	}
+	if err := c.restore(); err != nil {
+		c.lines, c.newline = saved, newline
+		c.mark()
+		return false, err
+	}
+	c.mark()
+	return len(indexes) > 0, nil
+}
+
+// Remove removes cron job entries matched by id or command.
+// returns false if job not exists.
+// returns error if variables of environment block can not be restored.
+func (c *Crontab) Remove(job CronJob) (bool, error) {
+	saved := slices.Clone(c.lines)
+	indexes := c.find(job)
+	for n := len(indexes) - 1; n >= 0; n-- {
+		from, to := c.span(indexes[n])
+		c.replace(from, to)
+	}
+	if err := c.restore(); err != nil {
+		c.lines = saved
+		c.mark()
+		return false, err
+	}
+	return len(indexes) > 0, nil
+}
+
+// Env returns value of global environment variable.
+func (c *Crontab) Env(key string) (string, bool) {
+	if i := c.env(key); i >= 0 {
+		return c.lines[i].Value, true
+	}
+	return "", false
+}
+
+// SetEnv sets global environment variable. existing assignment updated
+// in place, otherwise added after last global environment line
+// before first cron job (or before first cron job).
+// returns error on invalid name or value with new line.
+func (c *Crontab) SetEnv(key, value string) error {
+	if !isEnvName(key) {
+		return fmt.Errorf("invalid cron environment name %q", key)
+	} else if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("invalid cron environment value %q of %s", value, key)
+	}
+
+	line := parseCrontabLine(key+"="+quoteEnv(value), c.system)
+	if i := c.env(key); i >= 0 {
+		c.replace(i, i+1, line)
+		return c.restore()
+	}
+
+	at, last := len(c.lines), -1
+	for i, l := range c.lines {
+		if l.Kind == CrontabEntry {
+			at, _ = c.span(i)
+			break
+		} else if l.Kind == CrontabEnv {
+			last = i
+		}
+	}
+	if last >= 0 && last < at {
+		at = last + 1
+	}
+	c.replace(at, at, line)
+	c.newline = true
+	return c.restore()
+}
+
+// Envs returns global environment variables. environment
+// blocks of cron jobs not included.
+func (c *Crontab) Envs() map[string]string {
+	result := make(map[string]string)
+	owned := c.owned()
+	for i, line := range c.lines {
+		if _, ok := result[line.Key]; line.Kind == CrontabEnv && !owned[i] && !ok {
+			result[line.Key] = line.Value
+		}
+	}
+	return result
+}
+
+// SetTagEnv sets environment variable of managed cron jobs with tag
+// as per-job environment block, so variable applies to the group of
+// jobs without leaking to other entries. returns number of updated jobs.
+func (c *Crontab) SetTagEnv(tag, key, value string) (int, error) {
+	return c.tagEnv(tag, func(driver *cronDriver) {
+		driver.envs = setEnvs(driver.envs, key, value)
+	})
+}
+
+// UnsetTagEnv removes environment variable from environment block of
+// managed cron jobs with tag. returns number of updated jobs.
+func (c *Crontab) UnsetTagEnv(tag, key string) (int, error) {
+	return c.tagEnv(tag, func(driver *cronDriver) {
+		driver.envs = slices.DeleteFunc(driver.envs, func(env [2]string) bool {
+			return env[0] == key
+		})
+	})
+}
+
+// UnsetEnv removes global environment variable.
+// returns error if variables of environment block can not be restored.
+func (c *Crontab) UnsetEnv(key string) (bool, error) {
+	i := c.env(key)
+	if i < 0 {
+		return false, nil
+	}
+	saved := slices.Clone(c.lines)
+	c.replace(i, i+1)
+	if err := c.restore(); err != nil {
+		c.lines = saved
+		c.mark()
+		return false, err
+	}
+	return true, nil
+}
+
+// String serializes crontab into content.
+func (c *Crontab) String() string {
+	var result strings.Builder
+	for i, line := range c.lines {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		result.WriteString(line.Raw)
+	}
+	if c.newline && len(c.lines) > 0 {
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// find finds index of entries of cron job.
+func (c *Crontab) find(job CronJob) []int {
+	id := cronID(job)
+	command := cronCommand(job)
+
+	// Find marked entries
+	result := make([]int, 0)
+	if id != "" {
+		for i, line := range c.lines {
+			if line.Kind == CrontabEntry && c.marker(i) == id {
+				result = append(result, i)
+			}
+		}
+		if len(result) > 0 {
+			return result
+		}
+	}
+
+	// Find unmanaged entries by command (and user column of system crontab)
+	for i, line := range c.lines {
+		if line.Kind == CrontabEntry && c.marker(i) == "" && cronCommand(line.Job) == command &&
+			(!c.system || cronUser(line.Job) == cronUser(job)) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// marker get managed marker id of entry at index i.
+func (c *Crontab) marker(i int) string {
+	return c.attrs(i)["id"]
+}
+
+// attrs get managed marker attributes of entry at index i.
+func (c *Crontab) attrs(i int) map[string]string {
+	from, _ := c.span(i)
+	if attrs, ok := parseMarker(c.lines[from].Raw); ok && from < i {
+		return attrs
+	}
+	return nil
+}
+
+// mark sets id, tags and environment block of cron jobs from
+// marker comments and environment lines wrapped around entries.
+func (c *Crontab) mark() {
+	for i, line := range c.lines {
+		if driver, ok := line.Job.(*cronDriver); ok && line.Kind == CrontabEntry {
+			attrs := c.attrs(i)
+			driver.id = attrs["id"]
+			driver.tags = nil
+			if attrs["tags"] != "" {
+				driver.tags = strings.Split(attrs["tags"], ",")
+			}
+
+			// Environment block wrapped around entry
+			driver.envs = nil
+			from, _ := c.span(i)
+			for j := from; j < i; j++ {
+				if env := c.lines[j]; env.Kind == CrontabEnv {
+					driver.envs = append(driver.envs, [2]string{env.Key, env.Value})
+				}
+			}
+		}
+	}
+}
+
+// tagEnv updates environment block of managed cron jobs with tag.
+func (c *Crontab) tagEnv(tag string, update func(driver *cronDriver)) (int, error) {
+	saved, count := slices.Clone(c.lines), 0
+	for i := len(c.lines) - 1; i >= 0; i-- {
+		driver, ok := c.lines[i].Job.(*cronDriver)
+		if !ok || c.lines[i].Kind != CrontabEntry || driver.id == "" || !slices.Contains(driver.tags, tag) {
+			continue
+		}
+		update(driver)
+		lines, err := c.jobLines(driver)
+		if err != nil {
+			c.lines = saved
+			c.mark()
+			return 0, err
+		}
+		from, to := c.span(i)
+		c.replace(from, to, lines...)
+		count++
+	}
+	if err := c.restore(); err != nil {
+		c.lines = saved
+		c.mark()
+		return 0, err
+	}
+	c.mark()
+	return count, nil
+}
+
+// owned get index of lines owned by entries (marker comments and environment blocks).
+func (c *Crontab) owned() map[int]bool {
+	owned := make(map[int]bool)
+	for i, line := range c.lines {
+		if line.Kind == CrontabEntry {
+			from, to := c.span(i)
+			for j := from; j < to; j++ {
+				owned[j] = true
+			}
+		}
+	}
+	return owned
+}
+
+// env finds index of first global environment line of key.
+// environment lines owned by entries (e.g. CRON_TZ wrapped around job) ignored.
+func (c *Crontab) env(key string) int {
+	owned := c.owned()
+	for i, line := range c.lines {
+		if line.Kind == CrontabEnv && line.Key == key && !owned[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// span returns lines range [from, to) owned by entry at index i.
+// entry owns marker comment and environment block wrapped around it.
+func (c *Crontab) span(i int) (int, int) {
+	if from, to, ok := c.block(i); ok {
+		return from, to
+	}
+	return c.legacy(i)
+}
+
+// block returns lines range [from, to) of marked entry at index i.
+// environment block declared by marker (env=KEY,... attribute) with
+// KEY=value lines before and KEY=previous reset lines after entry.
+func (c *Crontab) block(i int) (int, int, bool) {
+	// Find marker above environment lines before entry
+	m := i - 1
+	for m >= 0 && c.lines[m].Kind == CrontabEnv {
+		m--
+	}
+	if m < 0 {
+		return i, i + 1, false
+	}
+	attrs, ok := parseMarker(c.lines[m].Raw)
+	if !ok {
+		return i, i + 1, false
+	}
+	keys := make([]string, 0)
+	if attrs["env"] != "" {
+		keys = strings.Split(attrs["env"], ",")
+	}
+
+	// Environment lines must match block keys of marker
+	if m+1+len(keys) != i {
+		return i, i + 1, false
+	}
+	for n, key := range keys {
+		if c.lines[m+1+n].Key != key {
+			return i, i + 1, false
+		}
+	}
+
+	// Owned reset lines
+	to := i + 1
+	for _, key := range keys {
+		if to >= len(c.lines) || c.lines[to].Kind != CrontabEnv || c.lines[to].Key != key {
+			break
+		}
+		to++
+	}
+	return m, to, true
+}
+
+// legacy returns lines range [from, to) of unmarked environment block
+// wrapped around entry at index i (KEY=value lines before and KEY=
+// reset lines after), as written by hand.
+func (c *Crontab) legacy(i int) (int, int) {
+	// Collect reset lines after entry
+	resets := make(map[string]bool)
+	for j := i + 1; j < len(c.lines); j++ {
+		line := c.lines[j]
+		if line.Kind != CrontabEnv || line.Value != "" || resets[line.Key] {
+			break
+		}
+		resets[line.Key] = true
+	}
+
+	// Collect assignment lines before entry
+	from := i
+	owned := make(map[string]bool)
+	for from > 0 {
+		line := c.lines[from-1]
+		if line.Kind != CrontabEnv || line.Value == "" || !resets[line.Key] || owned[line.Key] {
+			break
+		}
+		owned[line.Key] = true
+		from--
+	}
+
+	// Owned reset lines
+	to := i + 1
+	for to < len(c.lines) && c.lines[to].Kind == CrontabEnv && owned[c.lines[to].Key] {
+		delete(owned, c.lines[to].Key)
+		to++
+	}
+	return from, to
+}
+
+// restore sets reset lines of marked environment blocks to value effective
+// before block, or cron daemon default, so block variables not leak to
+// following entries. reset lines without value and no restorable value
+// returned as error, stale values kept.
+func (c *Crontab) restore() error {
+	for i, line := range c.lines {
+		if line.Kind != CrontabEntry {
+			continue
+		}
+		from, to, ok := c.block(i)
+		if !ok {
+			continue
+		}
+		for j := i + 1; j < to; j++ {
+			reset := c.lines[j]
+			value, ok := c.before(from, reset.Key)
+			if !ok {
+				value, ok = c.daemonEnv(reset.Key)
+			}
+			if !ok && reset.Value == "" {
+				return fmt.Errorf("can not restore %s after environment block of %q, set global %s first", reset.Key, cronCommand(line.Job), reset.Key)
+			} else if ok && (value != reset.Value || reset.Raw != reset.Key+"="+quoteEnv(value)) {
+				c.lines[j] = parseCrontabLine(reset.Key+"="+quoteEnv(value), c.system)
+			}
+		}
+	}
+	return nil
+}
+
+// before get value of environment variable effective at index i.
+func (c *Crontab) before(i int, key string) (string, bool) {
+	for j := i - 1; j >= 0; j-- {
+		if line := c.lines[j]; line.Kind == CrontabEnv && line.Key == key {
+			return line.Value, true
+		}
+	}
+	return "", false
+}
+
+// daemonEnv get default value of cron daemon environment variable.
+// mail and login variables default to owner of user crontab.
+func (c *Crontab) daemonEnv(key string) (string, bool) {
+	switch key {
+	case "SHELL":
+		return "/bin/sh", true
+	case "PATH":
+		return "/usr/bin:/bin", true
+	case "MAILTO", "LOGNAME", "USER":
+		return c.owner, c.owner != "" && !c.system
+	case "CRON_TZ":
+		return daemonZone()
+	}
+	return "", false
+}
+
+// replace replaces lines range [from, to) with new lines.
+func (c *Crontab) replace(from, to int, lines ...CrontabLine) {
+	result := make([]CrontabLine, 0, len(c.lines)-(to-from)+len(lines))
+	result = append(result, c.lines[:from]...)
+	result = append(result, lines...)
+	result = append(result, c.lines[to:]...)
+	c.lines = result
+}
+
+// jobLines get crontab lines of cron job.
+func (c *Crontab) jobLines(job CronJob) ([]CrontabLine, error) {
+	var texts []string
+	var err error
+	if driver, ok := job.(*cronDriver); ok {
+		texts, err = driver.lines(c.system)
+	} else {
+		texts = []string{job.Compile()}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CrontabLine, 0, len(texts))
+	for _, text := range texts {
+		result = append(result, parseCrontabLine(text, c.system))
+	}
+	return result, nil
+}
+
+// parseCrontab parses crontab content into document.
+func parseCrontab(content string, system bool) *Crontab {
+	tab := new(Crontab)
+	tab.system = system
+	if content == "" {
+		return tab
+	}
+
+	tab.newline = strings.HasSuffix(content, "\n")
+	content = strings.TrimSuffix(content, "\n")
+	for _, raw := range strings.Split(content, "\n") {
+		tab.lines = append(tab.lines, parseCrontabLine(raw, system))
+	}
+	tab.mark()
+	return tab
+}
+
+// parseCrontabLine parses single crontab line.
+func parseCrontabLine(raw string, system bool) CrontabLine {
+	line := CrontabLine{Raw: raw}
+	text := strings.TrimSpace(raw)
+	switch {
+	case text == "":
+		line.Kind = CrontabBlank
+	case strings.HasPrefix(text, "#"):
+		line.Kind = CrontabComment
+	default:
+		if key, value, ok := parseEnv(text); ok {
+			line.Kind = CrontabEnv
+			line.Key = key
+			line.Value = value
+		} else if job, err := parseCronLine(text, system); err == nil {
+			line.Kind = CrontabEntry
+			line.Job = job
+		} else {
+			line.Kind = CrontabUnknown
+		}
+	}
+	return line
+}
+
+// cronID get id of cron job.
+func cronID(job CronJob) string {
+	if driver, ok := job.(*cronDriver); ok {
+		return driver.id
+	}
+	return ""
+}
+
+// cronUser get user of cron job (default root).
+func cronUser(job CronJob) string {
+	if driver, ok := job.(*cronDriver); ok && driver.user != "" {
+		return driver.user
+	}
+	return "root"
+}
+
+// cronCommand get command of cron job as written to crontab.
+func cronCommand(job CronJob) string {
+	if driver, ok := job.(*cronDriver); ok {
+		return driver.wrapped()
+	}
+	return ""
+}
@@ -0,0 +1,463 @@
+package gounix_test
+
+import (
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mekramy/gounix"
+)
+
+const crontabContent = `# maintained by ops team
+MAILTO = "ops@example.com"
+PATH=/usr/local/bin:/usr/bin:/bin
+
+# nightly backup
+0 2 * * * /usr/bin/backup   --full
+not a valid line
+
+CRON_TZ=Europe/Berlin
+30 2 * * * report
+CRON_TZ=
+@reboot start-agent
+`
+
+func TestCrontabRoundTrip(t *testing.T) {
+	tab := gounix.ParseCrontab(crontabContent)
+	if result := tab.String(); result != crontabContent {
+		t.Errorf("Expected byte-for-byte round trip, got:\n%s", result)
+	}
+
+	kinds := []gounix.CrontabLineKind{
+		gounix.CrontabComment, gounix.CrontabEnv, gounix.CrontabEnv, gounix.CrontabBlank,
+		gounix.CrontabComment, gounix.CrontabEntry, gounix.CrontabUnknown, gounix.CrontabBlank,
+		gounix.CrontabEnv, gounix.CrontabEntry, gounix.CrontabEnv, gounix.CrontabEntry,
+	}
+	lines := tab.Lines()
+	if len(lines) != len(kinds) {
+		t.Fatalf("Expected %d lines, got %d", len(kinds), len(lines))
+	}
+	for i, line := range lines {
+		if line.Kind != kinds[i] {
+			t.Errorf("Expected kind %d on line %d (%s), got %d", kinds[i], i+1, line.Raw, line.Kind)
+		}
+	}
+
+	if mail, ok := tab.Env("MAILTO"); !ok || mail != "ops@example.com" {
+		t.Errorf("Expected MAILTO ops@example.com, got %s", mail)
+	}
+	if _, ok := tab.Env("CRON_TZ"); ok {
+		t.Error("Expected CRON_TZ owned by job, not global")
+	}
+	if len(tab.Jobs()) != 3 {
+		t.Errorf("Expected 3 jobs, got %d", len(tab.Jobs()))
+	}
+}
+
+func TestCrontabEdit(t *testing.T) {
+	tab := gounix.ParseCrontab(crontabContent)
+	tab.Set(gounix.NewCronJob("/usr/bin/backup   --full", nil).Daily().SetHour(3))
+	tab.Remove(gounix.NewCronJob("report", nil))
+	tab.SetEnv("PATH", "/usr/bin:/bin")
+	tab.SetEnv("TZ", "UTC")
+	tab.UnsetEnv("MAILTO")
+	tab.Set(gounix.NewCronJob("cleanup", nil).Daily())
+
+	expected := `# maintained by ops team
+PATH=/usr/bin:/bin
+TZ=UTC
+
+# nightly backup
+0 3 * * * /usr/bin/backup   --full
+not a valid line
+
+@reboot start-agent
+0 0 * * * cleanup
+`
+	if result := tab.String(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestCrontabEnvBlocks(t *testing.T) {
+	tab := gounix.ParseCrontab(crontabContent)
+	tab.Set(gounix.NewCronJob("sync", nil).ID("sync").Tags("billing").Daily().CrontabEnv("MAILTO", "billing@example.com"))
+	tab.Set(gounix.NewCronJob("invoice", nil).ID("invoice").Tags("billing").Daily())
+	tab.Set(gounix.NewCronJob("cleanup", nil).Daily())
+	if n, err := tab.SetTagEnv("billing", "SHELL", "/bin/bash"); err != nil || n != 2 {
+		t.Errorf("Expected 2 tagged jobs updated, got %d (%v)", n, err)
+	}
+
+	expected := crontabContent + `# gounix:id=sync tags=billing env=MAILTO,SHELL
+MAILTO=billing@example.com
+SHELL=/bin/bash
+0 0 * * * sync
+MAILTO=ops@example.com
+SHELL=/bin/sh
+# gounix:id=invoice tags=billing env=SHELL
+SHELL=/bin/bash
+0 0 * * * invoice
+SHELL=/bin/sh
+0 0 * * * cleanup
+`
+	if result := tab.String(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+
+	envs := tab.Envs()
+	if len(envs) != 2 || envs["MAILTO"] != "ops@example.com" || envs["PATH"] != "/usr/local/bin:/usr/bin:/bin" {
+		t.Errorf("Expected global MAILTO and PATH only, got %v", envs)
+	}
+
+	tab.UnsetTagEnv("billing", "SHELL")
+	tab.UnsetTagEnv("billing", "MAILTO")
+	tab.Remove(gounix.NewCronJob("cleanup", nil))
+	expected = crontabContent + `# gounix:id=sync tags=billing
+0 0 * * * sync
+# gounix:id=invoice tags=billing
+0 0 * * * invoice
+`
+	if result := tab.String(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+
+	if _, err := gounix.ParseCrontab("").Set(gounix.NewCronJob("sync", nil).CrontabEnv("HOME", "/srv")); err == nil {
+		t.Error("Expected error on unrestorable environment block")
+	}
+}
+
+func TestCrontabEnvRestore(t *testing.T) {
+	tab := gounix.ParseCrontab("MAILTO=ops@example.com\nPATH=/opt/bin:/usr/bin:/bin\n")
+	tab.Set(gounix.NewCronJob("sync", nil).ID("sync").Daily().CrontabEnv("MAILTO", "").CrontabEnv("PATH", "/srv/bin"))
+	tab.Set(gounix.NewCronJob("report", nil).Daily())
+
+	// Evaluate environment as cron daemon does
+	effective := func(tab *gounix.Crontab, command string) map[string]string {
+		envs := make(map[string]string)
+		for _, line := range tab.Lines() {
+			if line.Kind == gounix.CrontabEnv {
+				envs[line.Key] = line.Value
+			} else if line.Kind == gounix.CrontabEntry && strings.HasSuffix(line.Raw, " "+command) {
+				return maps.Clone(envs)
+			}
+		}
+		return nil
+	}
+
+	data := map[string]map[string]string{
+		"sync":   {"MAILTO": "", "PATH": "/srv/bin"},
+		"report": {"MAILTO": "ops@example.com", "PATH": "/opt/bin:/usr/bin:/bin"},
+	}
+	for command, expected := range data {
+		if result := effective(tab, command); !maps.Equal(result, expected) {
+			t.Errorf("Expected %v, got %v", expected, result)
+		} else {
+			t.Logf("Test passed on %s", command)
+		}
+	}
+
+	// Changed global value restored after block
+	tab.SetEnv("MAILTO", "root")
+	if result := effective(tab, "report"); result["MAILTO"] != "root" {
+		t.Errorf("Expected MAILTO root, got %v", result)
+	}
+	if envs := tab.Envs(); len(envs) != 2 || envs["MAILTO"] != "root" {
+		t.Errorf("Expected global MAILTO and PATH only, got %v", envs)
+	}
+}
+
+func TestCrontabEnvInjection(t *testing.T) {
+	tab := gounix.ParseCrontab(crontabContent)
+	tab.Set(gounix.NewCronJob("sync", nil).ID("sync").Tags("billing").Daily())
+
+	tests := map[string]func() error{
+		"global value": func() error {
+			return tab.SetEnv("MAILTO", "x\n* * * * * root evil")
+		},
+		"global name": func() error {
+			return tab.SetEnv("MAILTO=x\n*", "root")
+		},
+		"tag value": func() error {
+			_, err := tab.SetTagEnv("billing", "MAILTO", "x\r\n* * * * * evil")
+			return err
+		},
+		"tag name": func() error {
+			_, err := tab.SetTagEnv("billing", "MAILTO env=PATH", "root")
+			return err
+		},
+	}
+	expected := tab.String()
+	for name, call := range tests {
+		if err := call(); err == nil {
+			t.Errorf("Expected error on %s", name)
+		} else if result := tab.String(); result != expected {
+			t.Errorf("Expected unchanged crontab on %s, got:\n%s", name, result)
+		} else {
+			t.Logf("Test passed on %s", name)
+		}
+	}
+}
+
+func TestCrontabEnvUnbalanced(t *testing.T) {
+	content := "APP=x\n# gounix:id=a env=APP\nAPP=a\n0 0 * * * a\nAPP=\n0 0 * * * b\n"
+	tab := gounix.ParseCrontab(content)
+
+	// Block can not be restored without global value
+	if removed, err := tab.UnsetEnv("APP"); err == nil || removed {
+		t.Errorf("Expected restore error, got %t (%v)", removed, err)
+	}
+	if result := tab.String(); result != content {
+		t.Errorf("Expected unchanged crontab, got:\n%s", result)
+	}
+
+	tab = gounix.ParseCrontab(strings.TrimPrefix(content, "APP=x\n"))
+	if removed, err := tab.Remove(gounix.NewCronJob("b", nil)); err == nil || removed {
+		t.Errorf("Expected restore error, got %t (%v)", removed, err)
+	}
+}
+
+func TestCrontabNativeTZ(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Berlin"); err != nil {
+		t.Skip("Europe/Berlin timezone not available")
+	}
+	fsys := gounix.NewMemoryFS()
+	gounix.SetFileSystem(fsys)
+	defer gounix.SetFileSystem(nil)
+	tz := gounix.NewTZ().Location("Europe/Berlin").Mode(gounix.TZNative)
+
+	// Daemon timezone unknown
+	tab := gounix.ParseCrontab("")
+	if _, err := tab.Set(gounix.NewCronJob("report", tz).Daily()); err == nil {
+		t.Error("Expected error on unknown daemon timezone")
+	}
+
+	// Daemon timezone restored after job
+	if err := fsys.WriteFile("/etc/timezone", []byte("Asia/Tehran\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tab.Set(gounix.NewCronJob("report", tz).Daily())
+	expected := "# gounix:env=CRON_TZ\nCRON_TZ=Europe/Berlin\n0 0 * * * report\nCRON_TZ=Asia/Tehran\n"
+	if result := tab.String(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+
+	// Global CRON_TZ restored after job
+	tab.SetEnv("CRON_TZ", "UTC")
+	expected = "CRON_TZ=UTC\n# gounix:env=CRON_TZ\nCRON_TZ=Europe/Berlin\n0 0 * * * report\nCRON_TZ=UTC\n"
+	if result := tab.String(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestCrontabManagedEntries(t *testing.T) {
+	tab := gounix.ParseCrontab(`# gounix:id=backup-db
+0 2 * * * /usr/bin/backup --db main
+# gounix:id=backup-logs
+0 2 * * * /usr/bin/backup --db main
+0 4 * * * /usr/bin/cleanup
+`)
+
+	// Update by id and keep colliding command untouched
+	tab.Set(gounix.NewCronJob("/usr/bin/backup --db main --full", nil).ID("backup-db").Daily().SetHour(22))
+	// Adopt legacy entry matched by command
+	tab.Set(gounix.NewCronJob("/usr/bin/cleanup", nil).ID("cleanup").Daily().SetHour(23))
+	// Remove by id
+	tab.Remove(gounix.NewCronJob("", nil).ID("backup-logs"))
+
+	expected := `# gounix:id=backup-db
+0 22 * * * /usr/bin/backup --db main --full
+# gounix:id=cleanup
+0 23 * * * /usr/bin/cleanup
+`
+	if result := tab.String(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+
+	if job, ok := tab.FindID("cleanup"); !ok || job.Compile() != "0 23 * * * /usr/bin/cleanup" {
+		t.Error("Expected cleanup job found by id")
+	}
+}
+
+func TestCrontabHostileCommands(t *testing.T) {
+	commands := []string{
+		`echo "quoted" > /tmp/out`,
+		`echo $HOME $(id -u) ${PATH}`,
+		"echo `whoami`; rm -rf /tmp/x",
+		`printf 'a\nb\\c' \\ end`,
+		`date +%Y-%m-%d_%H:%M`,
+		`echo \% already escaped \\%`,
+		`echo "it's" '"double"' && exit 1 || true`,
+	}
+
+	tab := gounix.ParseCrontab("")
+	for _, command := range commands {
+		tab.Set(gounix.NewCronJob(command, nil).Daily())
+	}
+
+	parsed := gounix.ParseCrontab(tab.String())
+	for _, command := range commands {
+		job, ok := parsed.Find(command)
+		if !ok {
+			t.Errorf("Expected round trip of %s", command)
+			continue
+		}
+		if line := job.Compile(); strings.Count(line, "%") != strings.Count(line, `\%`) {
+			t.Errorf("Expected escaped %% on %s", line)
+		} else {
+			t.Logf("Test passed on %s", line)
+		}
+	}
+
+	if len(parsed.Jobs()) != len(commands) {
+		t.Errorf("Expected %d jobs, got %d", len(commands), len(parsed.Jobs()))
+	}
+
+	if err := gounix.NewCronJob("echo a\n* * * * * evil", nil).Validate(); err == nil {
+		t.Error("Expected error on command with new line")
+	}
+}
+
+func TestCrontabRawPercent(t *testing.T) {
+	// Unescaped % (stdin) of adopted line kept on rewrite
+	content := "0 1 * * * mail -s report root%body \\% done%\n"
+	tab := gounix.ParseCrontab(content)
+	job, ok := tab.Find(`mail -s report root%body % done%`)
+	if !ok {
+		t.Fatalf("Expected adopted job, got %v", tab.Jobs())
+	}
+	if _, err := tab.Set(job); err != nil {
+		t.Fatal(err)
+	}
+	if result := tab.String(); result != content {
+		t.Errorf("Expected %q, got %q", content, result)
+	} else {
+		t.Logf("Test passed on %q", result)
+	}
+
+	// Modified command can not keep stdin
+	if err := job.ID("mail").NoOverlap().Validate(); err == nil {
+		t.Error("Expected error on rewrite of unescaped %")
+	}
+}
+
+func TestSystemCrontab(t *testing.T) {
+	tab := gounix.ParseSystemCrontab(`SHELL=/bin/sh
+*/5 * * * * deploy /srv/app/bin/sync
+@reboot root /srv/app/bin/start
+`)
+	if len(tab.Jobs()) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d", len(tab.Jobs()))
+	}
+
+	store := gounix.NewCronDFile("app")
+	tab.Set(gounix.NewCronJob("/srv/app/bin/sync", nil).Store(store).User("deploy").EveryXMinutes(10))
+	tab.Set(gounix.NewCronJob("/srv/app/bin/report", nil).Store(store).Daily())
+	tab.Set(gounix.NewCronJob("/srv/app/bin/sync", nil).Store(store).User("backup").EveryXMinutes(30))
+
+	expected := `SHELL=/bin/sh
+*/10 * * * * deploy /srv/app/bin/sync
+@reboot root /srv/app/bin/start
+0 0 * * * root /srv/app/bin/report
+*/30 * * * * backup /srv/app/bin/sync
+`
+	if result := tab.String(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestCrontabEntries(t *testing.T) {
+	tab := gounix.ParseCrontab("0 4 * * * /usr/bin/cleanup\n")
+	tab.Set(gounix.NewCronJob("/usr/bin/backup", nil).ID("backup").Tags("db", "nightly").Daily().SetHour(2))
+
+	expected := "0 4 * * * /usr/bin/cleanup\n# gounix:id=backup tags=db,nightly\n0 2 * * * /usr/bin/backup\n"
+	if result := tab.String(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+
+	entries := gounix.ParseCrontab(tab.String()).Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Managed || entries[0].Schedule != "0 4 * * *" || entries[0].Command != "/usr/bin/cleanup" {
+		t.Errorf("Unexpected unmanaged entry %+v", entries[0])
+	}
+	if !entries[1].Managed || entries[1].ID != "backup" || strings.Join(entries[1].Tags, ",") != "db,nightly" {
+		t.Errorf("Unexpected managed entry %+v", entries[1])
+	}
+}
+
+// memoryCrontab in-memory crontab store.
+type memoryCrontab struct {
+	content string
+	writes  int
+}
+
+func (m *memoryCrontab) Read() (*gounix.Crontab, error) {
+	return gounix.ParseCrontab(m.content), nil
+}
+
+func (m *memoryCrontab) Write(tab *gounix.Crontab) error {
+	m.content = tab.String()
+	m.writes++
+	return nil
+}
+
+func (m *memoryCrontab) System() bool {
+	return false
+}
+
+func TestCronBatchValidation(t *testing.T) {
+	store := &memoryCrontab{content: crontabContent}
+	results, err := gounix.NewCronBatch(store).
+		Install(gounix.NewCronJob("cleanup", nil).Daily()).
+		Install(gounix.NewCronJob("invalid", nil).SetMinute(75)).
+		Uninstall(gounix.NewCronJob("report", nil)).
+		Apply()
+	if err == nil {
+		t.Error("Expected validation error")
+	}
+	if len(results) != 3 || results[0].Err != nil || results[1].Err == nil || results[2].Err != nil {
+		t.Errorf("Expected second job error only, got %v", results)
+	}
+	if store.writes != 0 || store.content != crontabContent {
+		t.Errorf("Expected nothing written, got %d writes", store.writes)
+	}
+}
+
+func TestCronBatchApply(t *testing.T) {
+	gounix.SetCronRestart(gounix.CronRestartNever)
+	defer gounix.SetCronRestart(gounix.CronRestartAuto)
+
+	store := &memoryCrontab{content: crontabContent}
+	results, err := gounix.NewCronBatch(store).
+		Runner(gounix.NewRecordingRunner()).
+		Install(gounix.NewCronJob("/usr/bin/backup   --full", nil).Daily().SetHour(3)).
+		Install(gounix.NewCronJob("cleanup", nil).Daily()).
+		Uninstall(gounix.NewCronJob("report", nil)).
+		Apply()
+	if err != nil {
+		t.Fatalf("Expected batch applied, got %v", err)
+	}
+	existed := []bool{true, false, true}
+	for i, result := range results {
+		if result.Existed != existed[i] || result.Err != nil {
+			t.Errorf("Expected existed %t on job %d, got %t (%v)", existed[i], i+1, result.Existed, result.Err)
+		}
+	}
+
+	expected := `# maintained by ops team
+MAILTO = "ops@example.com"
+PATH=/usr/local/bin:/usr/bin:/bin
+
+# nightly backup
+0 3 * * * /usr/bin/backup   --full
+not a valid line
+
+@reboot start-agent
+0 0 * * * cleanup
+`
+	if store.writes != 1 || store.content != expected {
+		t.Errorf("Expected single write:\n%s\ngot %d writes:\n%s", expected, store.writes, store.content)
+	}
+}
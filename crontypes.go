@@ -1,5 +1,10 @@
 package gounix
 
+import (
+	"strconv"
+	"time"
+)
+
 // Weekday represents a day of the week for cron job.
 type Weekday int
 
@@ -22,11 +27,99 @@ func (wd Weekday) Real() int {
 	return int(wd) - 1
 }
 
+// CronFieldError represents invalid input of a cron job field.
+type CronFieldError struct {
+	Field  string // minute, hour, day of month, month or day of week
+	Value  string // invalid input
+	Reason string
+}
+
+func (e *CronFieldError) Error() string {
+	return "invalid cron " + e.Field + " " + strconv.Quote(e.Value) + ": " + e.Reason
+}
+
+// CronScheduleWarning represents schedule that never fires or
+// can not be compiled as intended.
+type CronScheduleWarning struct {
+	Schedule string
+	Reason   string
+}
+
+func (w *CronScheduleWarning) Error() string {
+	return "cron schedule " + strconv.Quote(w.Schedule) + " " + w.Reason
+}
+
+// CronRun represents a recorded run of a cron job.
+type CronRun struct {
+	Start    time.Time
+	Duration time.Duration
+	ExitCode int // 75 for runs skipped by NoOverlap, 124 for Timeout
+}
+
+// CronEntry represents an installed cron job.
+type CronEntry struct {
+	Job      CronJob
+	Schedule string // five fields expression or @reboot
+	Command  string // user command without wrapper and exclusion guard of managed jobs
+	Owner    string // crontab user or user column of system crontab
+	Source   string // user crontab, /etc/crontab or /etc/cron.d file path
+	ID       string // marker id of managed jobs
+	Tags     []string
+	Managed  bool // job managed by gounix marker
+}
+
+// CronListOptions represents source and filters of ListCronJobs.
+type CronListOptions struct {
+	Users         []string // user crontabs to list, empty means root
+	AllUsers      bool     // list crontabs of all users from cron spool
+	System        bool     // include /etc/crontab and /etc/cron.d files
+	CommandPrefix string   // filter by command prefix
+	ID            string   // filter by marker id
+	Tag           string   // filter by marker tag
+	ManagedOnly   bool     // filter gounix managed jobs
+	Runner        Runner   // command runner of spool listing and crontab reads (default runner if nil)
+}
+
+// CronBatchAction represents staged action of cron batch.
+type CronBatchAction int
+
+const (
+	CronBatchInstall   CronBatchAction = 0 // install or update job
+	CronBatchUninstall CronBatchAction = 1 // remove job
+)
+
+// CronBatchResult represents result of staged cron batch action.
+type CronBatchResult struct {
+	Job     CronJob
+	Action  CronBatchAction
+	Existed bool  // job existed before batch applied
+	Err     error // validation error, nothing written if any job invalid
+}
+
+// TZMode determines how location based timezone applied to cron jobs.
+type TZMode int
+
+const (
+	// TZTranslate translates schedule to the daemon timezone using location offset
+	// effective at instant pinned by CronTZ.OffsetAt, or current time of each
+	// compile if not pinned. jobs must re-installed after each DST transition
+	// (see CronTZ.NextTransition). works on all cron daemons.
+	TZTranslate TZMode = 0
+	// TZNative keeps schedule in location time and emits CRON_TZ= line before job,
+	// previous CRON_TZ or daemon timezone restored after job.
+	// requires daemon with CRON_TZ support (e.g. cronie).
+	TZNative TZMode = 1
+)
+
 // CronTZ represents a timezone for a cron job.
 type CronTZ struct {
-	hour    int
-	minute  int
-	weekend Weekday
+	hour     int
+	minute   int
+	weekend  Weekday
+	location *time.Location
+	mode     TZMode
+	at       time.Time
+	err      error
 }
 
 func (tz *CronTZ) Hour(hour int) *CronTZ {
@@ -43,3 +136,79 @@ func (tz *CronTZ) Weekend(weekend Weekday) *CronTZ {
 	tz.weekend = weekend
 	return tz
 }
+
+// Location sets IANA timezone (e.g. Europe/Berlin) with daylight saving support.
+// location overrides hour and minute offset.
+func (tz *CronTZ) Location(name string) *CronTZ {
+	tz.location, tz.err = time.LoadLocation(name)
+	return tz
+}
+
+// Mode sets how location applied to cron jobs (default TZTranslate).
+func (tz *CronTZ) Mode(mode TZMode) *CronTZ {
+	tz.mode = mode
+	return tz
+}
+
+// OffsetAt pins the instant used to resolve location offset on translation
+// (Compile, Install, NextRun). zero time means current time of each call,
+// so results of unpinned location change across DST transitions.
+func (tz *CronTZ) OffsetAt(t time.Time) *CronTZ {
+	tz.at = t
+	return tz
+}
+
+// NextTransition returns the next daylight saving transition of location after t.
+// returns false if location not set or has no transition in next year.
+func (tz *CronTZ) NextTransition(after time.Time) (time.Time, bool) {
+	if tz.location == nil {
+		return time.Time{}, false
+	}
+
+	// Find transition day
+	_, offset := after.In(tz.location).Zone()
+	from, to := after, after
+	for i := 0; i < 366; i++ {
+		to = from.Add(24 * time.Hour)
+		if _, o := to.In(tz.location).Zone(); o != offset {
+			break
+		}
+		from = to
+	}
+	if _, o := to.In(tz.location).Zone(); o == offset {
+		return time.Time{}, false
+	}
+
+	// Narrow down to second
+	for to.Sub(from) > time.Second {
+		mid := from.Add(to.Sub(from) / 2)
+		if _, o := mid.In(tz.location).Zone(); o == offset {
+			from = mid
+		} else {
+			to = mid
+		}
+	}
+	return to.Truncate(time.Second), true
+}
+
+// native checks if timezone must emitted as CRON_TZ line.
+func (tz *CronTZ) native() bool {
+	return tz != nil && tz.location != nil && tz.mode == TZNative
+}
+
+// offset get timezone offset to daemon timezone in minutes.
+func (tz *CronTZ) offset() int {
+	if tz == nil || tz.native() {
+		return 0
+	} else if tz.location == nil {
+		return tz.hour*60 + tz.minute
+	}
+
+	at := tz.at
+	if at.IsZero() {
+		at = time.Now()
+	}
+	_, offset := at.In(tz.location).Zone()
+	_, local := at.In(time.Local).Zone()
+	return (offset - local) / 60
+}
@@ -0,0 +1,225 @@
+package gounix
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// overlapExitCode exit code of runs skipped by no-overlap lock.
+const overlapExitCode = 75
+
+// cronWrapper execution wrapper options of cron job.
+type cronWrapper struct {
+	lock    bool
+	timeout time.Duration
+	log     string
+	env     [][2]string
+	history bool
+	file    string // history file
+}
+
+// enabled checks if any wrapper option enabled.
+func (w cronWrapper) enabled() bool {
+	return w.lock || w.timeout > 0 || w.log != "" || len(w.env) > 0 || w.history
+}
+
+// key get unique file key of cron job (id or command hash).
+func (c *cronDriver) key() string {
+	if c.id != "" {
+		return c.id
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(c.command))
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// stateFile get state file path of cron job. root jobs use system
+// directory, jobs of other users hidden file in home directory.
+func (c *cronDriver) stateFile(dir, ext string) string {
+	if c.user == "" || c.user == "root" {
+		return dir + "/gounix-" + c.key() + ext
+	}
+	home := "/home/" + c.user
+	if u, err := user.Lookup(c.user); err == nil && u.HomeDir != "" {
+		home = u.HomeDir
+	}
+	return home + "/.gounix-" + c.key() + ext
+}
+
+// lockPath get no-overlap lock file path of cron job.
+func (c *cronDriver) lockPath() string {
+	return c.stateFile("/run/lock", ".lock")
+}
+
+// historyPath get history file path of cron job.
+func (c *cronDriver) historyPath() string {
+	if c.wrapper.file != "" {
+		return c.wrapper.file
+	}
+	return c.stateFile("/var/log", ".jsonl")
+}
+
+// wrapped get command wrapped with execution wrapper.
+func (c *cronDriver) wrapped() string {
+	w := c.wrapper
+	if !w.enabled() {
+		return c.guard() + c.command
+	}
+
+	// Build command chain, symlinked lock file refused
+	parts := make([]string, 0)
+	if w.lock {
+		parts = append(parts, "[", "!", "-L", shellQuote(c.lockPath()), "]", "&&")
+	}
+	if len(w.env) > 0 {
+		parts = append(parts, "env")
+		for _, env := range w.env {
+			parts = append(parts, shellQuote(env[0]+"="+env[1]))
+		}
+	}
+	if w.lock {
+		parts = append(parts,
+			"flock", "-n", "-E", strconv.Itoa(overlapExitCode),
+			shellQuote(c.lockPath()),
+		)
+	}
+	if w.timeout > 0 {
+		seconds := int((w.timeout + time.Second - 1) / time.Second)
+		parts = append(parts, "timeout", strconv.Itoa(seconds)+"s")
+	}
+	parts = append(parts, "/bin/sh", "-c", shellQuote(c.command))
+	if w.log != "" {
+		parts = append(parts, ">>", shellQuote(w.log), "2>&1")
+	}
+	command := strings.Join(parts, " ")
+
+	// Record run history
+	if w.history {
+		command = "s=$(date +%s); " + command + "; c=$?; " +
+			`printf '{"start":%s,"duration":%s,"exit":%s}\n' "$s" "$(($(date +%s)-s))" "$c" >> ` +
+			shellQuote(c.historyPath())
+	}
+	return c.guard() + command
+}
+
+// guard get shell guard skipping runs on calendar excluded dates
+// of job timezone (e.g. case "$(date +%Y-%m-%d)" in 2026-12-25) exit 0;; esac; ).
+func (c *cronDriver) guard() string {
+	if c.exclude == nil {
+		return ""
+	}
+	patterns := c.exclude.patterns()
+	if len(patterns) == 0 {
+		return ""
+	}
+
+	date := "date +%Y-%m-%d"
+	if c.tz != nil && c.tz.location != nil {
+		date = "TZ=" + shellQuote(c.tz.location.String()) + " " + date
+	} else if offset := c.tz.offset(); offset != 0 {
+		date = "date -d @$(($(date +%s)+" + strconv.Itoa(offset*60) + ")) +%Y-%m-%d"
+	}
+	return `case "$(` + date + `)" in ` + strings.Join(patterns, "|") + ") exit 0;; esac; "
+}
+
+// unwrapCommand get user command of command wrapped with execution
+// wrapper or exclusion guard (e.g. flock ... /bin/sh -c 'backup' to backup).
+func unwrapCommand(command string) string {
+	if strings.HasPrefix(command, `case "$(`) {
+		if _, after, ok := strings.Cut(command, ") exit 0;; esac; "); ok {
+			command = after
+		}
+	}
+
+	// Unquote single quoted command of shell
+	_, quoted, ok := strings.Cut(command, "/bin/sh -c '")
+	if !ok {
+		return command
+	}
+	var result strings.Builder
+	for {
+		part, rest, ok := strings.Cut(quoted, "'")
+		result.WriteString(part)
+		if !ok || !strings.HasPrefix(rest, `\''`) {
+			return result.String()
+		}
+		result.WriteString("'")
+		quoted = rest[3:]
+	}
+}
+
+func (c *cronDriver) NoOverlap() CronJob {
+	c.wrapper.lock = true
+	return c
+}
+
+func (c *cronDriver) Timeout(d time.Duration) CronJob {
+	c.wrapper.timeout = d
+	return c
+}
+
+func (c *cronDriver) LogFile(path string) CronJob {
+	c.wrapper.log = path
+	return c
+}
+
+func (c *cronDriver) Env(key, value string) CronJob {
+	c.wrapper.env = append(c.wrapper.env, [2]string{key, value})
+	return c
+}
+
+func (c *cronDriver) RecordHistory(path string) CronJob {
+	c.wrapper.history = true
+	c.wrapper.file = path
+	return c
+}
+
+func (c *cronDriver) History(n int) ([]CronRun, error) {
+	if !c.wrapper.history {
+		return nil, errors.New("cron job history not recorded")
+	}
+
+	// Read history file
+	content, err := readFile(c.runner, c.historyPath())
+	if os.IsNotExist(err) {
+		return []CronRun{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Parse runs
+	runs := make([]CronRun, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		var record struct {
+			Start    int64 `json:"start"`
+			Duration int64 `json:"duration"`
+			Exit     int   `json:"exit"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+		runs = append(runs, CronRun{
+			Start:    time.Unix(record.Start, 0),
+			Duration: time.Duration(record.Duration) * time.Second,
+			ExitCode: record.Exit,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Last n runs
+	if n >= 0 && len(runs) > n {
+		runs = runs[len(runs)-n:]
+	}
+	return runs, nil
+}
@@ -0,0 +1,208 @@
+package gounix
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Escalation determines how privileged commands and file writes escalated.
+type Escalation int
+
+const (
+	// EscalationSudo prefixes privileged commands with non-interactive sudo (sudo -n).
+	EscalationSudo Escalation = 0
+	// EscalationNone runs privileged commands and file writes as current user.
+	EscalationNone Escalation = 1
+	// EscalationDoas prefixes privileged commands with non-interactive doas (doas -n).
+	EscalationDoas Escalation = 2
+	// EscalationPkexec prefixes privileged commands with pkexec.
+	EscalationPkexec Escalation = 3
+	// EscalationCustom prefixes privileged commands with custom prefix.
+	EscalationCustom Escalation = 4
+)
+
+// ErrNoPrivileges is returned when privileged command or file write
+// denied (e.g. sudo requires password or process not running as root).
+var ErrNoPrivileges = errors.New("privileges required")
+
+// escalation escalation policy and custom prefix.
+var (
+	escalation       = EscalationSudo
+	escalationPrefix []string
+)
+
+// SetEscalation sets escalation policy of privileged commands and file
+// writes (default EscalationSudo). prefix used by EscalationCustom
+// (e.g. "sudo", "-n", "-u", "root"). escalation skipped if process
+// running as root and commands executed by local runner.
+func SetEscalation(policy Escalation, prefix ...string) error {
+	if policy < EscalationSudo || policy > EscalationCustom {
+		return fmt.Errorf("invalid escalation policy %d", policy)
+	} else if policy == EscalationCustom && len(prefix) == 0 {
+		return errors.New("custom escalation requires prefix")
+	}
+	escalation = policy
+	escalationPrefix = append([]string(nil), prefix...)
+	return nil
+}
+
+// EscalationPrefix returns command prefix of escalation policy.
+func EscalationPrefix() []string {
+	switch escalation {
+	case EscalationNone:
+		return nil
+	case EscalationDoas:
+		return []string{"doas", "-n"}
+	case EscalationPkexec:
+		return []string{"pkexec"}
+	case EscalationCustom:
+		return append([]string(nil), escalationPrefix...)
+	}
+	return []string{"sudo", "-n"}
+}
+
+// escalate prefixes privileged command by escalation policy.
+func escalate(runner Runner, args ...string) []string {
+	prefix := EscalationPrefix()
+	if _, local := runnerOf(runner).(*localRunner); local && IsSudo() {
+		prefix = nil
+	}
+	return append(prefix, args...)
+}
+
+// runRoot runs privileged command with stdin. denied escalation returned as ErrNoPrivileges.
+func runRoot(runner Runner, stdin string, args ...string) (RunResult, error) {
+	result, err := run(runner, stdin, escalate(runner, args...)...)
+	if err != nil && deniedEscalation(result) {
+		return result, fmt.Errorf("%w, %s", ErrNoPrivileges, strings.TrimSpace(result.Stderr))
+	}
+	return result, err
+}
+
+// deniedEscalation checks if command failed by escalation tool.
+func deniedEscalation(result RunResult) bool {
+	switch escalation {
+	case EscalationSudo, EscalationDoas:
+		// Errors of escalation tool itself prefixed by tool name
+		return strings.HasPrefix(result.Stderr, "sudo:") || strings.HasPrefix(result.Stderr, "doas:")
+	case EscalationPkexec:
+		return result.ExitCode == 126 || result.ExitCode == 127
+	}
+	return !IsSudo() && strings.Contains(strings.ToLower(result.Stderr), "permission denied")
+}
+
+// direct checks if privileged files accessed through filesystem (staging
+// filesystem or local runner without escalation), otherwise through runner.
+func direct(runner Runner) bool {
+	_, local := runnerOf(runner).(*localRunner)
+	return staging() || (local && len(escalate(runner)) == 0)
+}
+
+// readFile reads privileged file, with escalated cat if files accessed through runner.
+func readFile(runner Runner, path string) ([]byte, error) {
+	if direct(runner) {
+		content, err := fileSystem.ReadFile(path)
+		return content, privilegeError(err)
+	}
+	if exists, err := fileExists(runner, path); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: syscall.ENOENT}
+	}
+	result, err := runRoot(runner, "", "cat", path)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(result.Stdout), nil
+}
+
+// readDir lists names of regular files in privileged directory,
+// with escalated find if files accessed through runner.
+func readDir(runner Runner, dir string) ([]string, error) {
+	result := make([]string, 0)
+	if direct(runner) {
+		entries, err := fileSystem.ReadDir(dir)
+		if err != nil {
+			return nil, privilegeError(err)
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				result = append(result, entry.Name())
+			}
+		}
+		return result, nil
+	}
+
+	if exists, err := fileExists(runner, dir); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, &fs.PathError{Op: "open", Path: dir, Err: syscall.ENOENT}
+	}
+	output, err := runRoot(runner, "", "find", dir, "-mindepth", "1", "-maxdepth", "1", "-type", "f", "-exec", "basename", "{}", ";")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(output.Stdout, "\n") {
+		if name != "" {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// writeFile writes privileged file, with escalated tee if files accessed through runner.
+func writeFile(runner Runner, path string, content []byte, perm os.FileMode) error {
+	if direct(runner) {
+		return privilegeError(fileSystem.WriteFile(path, content, perm))
+	}
+	if _, err := runRoot(runner, string(content), "tee", path); err != nil {
+		return err
+	}
+	_, err := runRoot(runner, "", "chmod", strconv.FormatUint(uint64(perm.Perm()), 8), path)
+	return err
+}
+
+// removeFile removes privileged file if exists.
+func removeFile(runner Runner, path string) error {
+	if direct(runner) {
+		err := fileSystem.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return privilegeError(err)
+	}
+	_, err := runRoot(runner, "", "rm", "-f", path)
+	return err
+}
+
+// symlink creates privileged symbolic link.
+func symlink(runner Runner, target, link string) error {
+	if direct(runner) {
+		return privilegeError(fileSystem.Symlink(target, link))
+	}
+	_, err := runRoot(runner, "", "ln", "-s", target, link)
+	return err
+}
+
+// runLive runs privileged command changing live host (e.g. systemctl restart).
+// skipped on staging filesystem.
+func runLive(runner Runner, args ...string) error {
+	if staging() {
+		return nil
+	}
+	_, err := runRoot(runner, "", args...)
+	return err
+}
+
+// privilegeError wraps permission error with ErrNoPrivileges.
+func privilegeError(err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return fmt.Errorf("%w, %s", ErrNoPrivileges, err)
+	}
+	return err
+}
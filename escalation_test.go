@@ -0,0 +1,73 @@
+package gounix_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mekramy/gounix"
+)
+
+func TestEscalation(t *testing.T) {
+	defer gounix.SetEscalation(gounix.EscalationSudo)
+
+	tests := map[gounix.Escalation]string{
+		gounix.EscalationSudo:   "sudo -n crontab -l",
+		gounix.EscalationNone:   "crontab -l",
+		gounix.EscalationDoas:   "doas -n crontab -l",
+		gounix.EscalationPkexec: "pkexec crontab -l",
+		gounix.EscalationCustom: "sudo -n -u root crontab -l",
+	}
+	for policy, expected := range tests {
+		if err := gounix.SetEscalation(policy, "sudo", "-n", "-u", "root"); err != nil {
+			t.Fatal(err)
+		}
+		runner := gounix.NewRecordingRunner()
+		gounix.NewCronJob("report", nil).Runner(runner).Exists()
+		if commands := runner.Commands(); !slices.Equal(commands, []string{expected}) {
+			t.Errorf("Expected %s, got %v", expected, commands)
+		} else {
+			t.Logf("Test passed on %s", expected)
+		}
+	}
+
+	if err := gounix.SetEscalation(gounix.EscalationCustom); err == nil {
+		t.Error("Expected error on custom escalation without prefix")
+	}
+}
+
+func TestEscalationDenied(t *testing.T) {
+	defer gounix.SetEscalation(gounix.EscalationSudo)
+
+	// Password prompt of non-interactive sudo
+	runner := gounix.NewRecordingRunner().
+		Reply("sudo -n crontab -l", gounix.RunResult{ExitCode: 1, Stderr: "sudo: a password is required\n"})
+	if _, err := gounix.NewCronJob("report", nil).Runner(runner).Exists(); !errors.Is(err, gounix.ErrNoPrivileges) {
+		t.Errorf("Expected %s, got %v", gounix.ErrNoPrivileges, err)
+	}
+
+	// Privileged file written by escalated tee
+	runner = gounix.NewRecordingRunner()
+	service := gounix.NewSystemdService("gounix-escalation-test", "/srv/api", "api").Runner(runner)
+	if _, err := service.Install(true); err != nil {
+		t.Fatal(err)
+	}
+	calls := runner.Calls()
+	expected := "sudo -n tee /etc/systemd/system/gounix-escalation-test.service"
+	if command := strings.Join(calls[1].Args, " "); command != expected {
+		t.Errorf("Expected %s, got %s", expected, command)
+	} else if !strings.Contains(calls[1].Stdin, "Description=gounix-escalation-test") {
+		t.Errorf("Expected unit content, got %q", calls[1].Stdin)
+	} else {
+		t.Logf("Test passed on %s", expected)
+	}
+
+	// Denied file write of escalated tee
+	runner = gounix.NewRecordingRunner().
+		Reply("sudo -n tee", gounix.RunResult{ExitCode: 1, Stderr: "sudo: a password is required\n"})
+	service = gounix.NewSystemdService("gounix-escalation-test", "/srv/api", "api").Runner(runner)
+	if _, err := service.Install(true); !errors.Is(err, gounix.ErrNoPrivileges) {
+		t.Errorf("Expected %s, got %v", gounix.ErrNoPrivileges, err)
+	}
+}
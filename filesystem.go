@@ -0,0 +1,64 @@
+package gounix
+
+import (
+	"io/fs"
+	"os"
+)
+
+// FileSystem filesystem of managed paths (/etc/nginx, /etc/systemd/system,
+// /etc/cron.d, /etc/anacrontab, /etc/crontab and cron spool). names are
+// absolute paths of target system.
+type FileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	// WriteFile writes file. staging filesystems create parent directories.
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	Remove(name string) error
+	// Symlink creates link to target. target kept as path of target system.
+	Symlink(target, link string) error
+	// Stat returns file info, symbolic links followed inside filesystem.
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+}
+
+// fileSystem filesystem of drivers.
+var fileSystem FileSystem = NewHostFS()
+
+// staging checks if drivers render configs into staging filesystem.
+func staging() bool {
+	_, host := fileSystem.(*hostFS)
+	return !host
+}
+
+// SetFileSystem sets filesystem of managed paths. nil means live host.
+// on staging filesystems (NewRootFS, NewMemoryFS) configs only rendered:
+// user crontabs written to cron spool, units enabled by .wants links and
+// commands changing live host (systemctl, daemon restarts) skipped.
+// at jobs can not be submitted or canceled on staging filesystems.
+func SetFileSystem(fsys FileSystem) {
+	if fsys == nil {
+		fsys = NewHostFS()
+	}
+	fileSystem = fsys
+}
+
+// NewHostFS creates filesystem of live host. privileged writes escalated
+// by escalation policy.
+func NewHostFS() FileSystem {
+	return new(hostFS)
+}
+
+// NewRootFS creates staging filesystem with root directory prefix
+// (e.g. chroot or image rootfs directory).
+func NewRootFS(root string) FileSystem {
+	fsys := new(rootFS)
+	fsys.root = root
+	return fsys
+}
+
+// NewMemoryFS creates empty in-memory staging filesystem.
+func NewMemoryFS() FileSystem {
+	fsys := new(memoryFS)
+	fsys.files = map[string]*memoryFile{"/": {dir: true}}
+	return fsys
+}
@@ -0,0 +1,173 @@
+package gounix_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mekramy/gounix"
+)
+
+func TestMemoryFS(t *testing.T) {
+	fsys := gounix.NewMemoryFS()
+	gounix.SetFileSystem(fsys)
+	defer gounix.SetFileSystem(nil)
+	runner := gounix.NewRecordingRunner()
+
+	// Render configs
+	if _, err := gounix.NewNginxReverseProxy("app", "8080").Domains("example.com").Runner(runner).Install(true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gounix.NewSystemdService("app", "/srv/app", "app").Runner(runner).Install(true); err != nil {
+		t.Fatal(err)
+	}
+	job := gounix.NewCronJob("backup", nil).ID("backup").Daily().SetHour(2).Runner(runner)
+	if _, err := job.Install(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := job.ToSystemdTimer("backup").Runner(runner).Install(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := job.ToAnacron("backup").Install(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gounix.NewCronJob("sync", nil).Store(gounix.NewCronDFile("app")).Install(); err != nil {
+		t.Fatal(err)
+	}
+	if commands := runner.Commands(); len(commands) != 0 {
+		t.Errorf("Expected no host commands, got %v", commands)
+	}
+
+	tests := map[string]string{
+		"/etc/nginx/sites-available/app":                          "server_name example.com;",
+		"/etc/nginx/sites-enabled/app":                            "server_name example.com;",
+		"/etc/systemd/system/app.service":                         "ExecStart=/usr/bin/sudo /srv/app/app",
+		"/etc/systemd/system/multi-user.target.wants/app.service": "Description=app",
+		"/etc/systemd/system/backup.timer":                        "OnCalendar=",
+		"/etc/systemd/system/timers.target.wants/backup.timer":    "OnCalendar=",
+		"/var/spool/cron/crontabs/root":                           "# gounix:id=backup\n0 2 * * * backup\n",
+		"/etc/anacrontab":                                         "\tbackup\t",
+		"/etc/cron.d/app":                                         "* * * * * root sync\n",
+	}
+	for name, expected := range tests {
+		if content, err := fsys.ReadFile(name); err != nil {
+			t.Errorf("Expected %s, got %v", name, err)
+		} else if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected %q in %s, got %q", expected, name, content)
+		} else {
+			t.Logf("Test passed on %s", name)
+		}
+	}
+
+	// Read rendered configs back
+	entries, err := gounix.ListCronJobs(gounix.CronListOptions{System: true, ManagedOnly: true})
+	if err != nil || len(entries) != 1 || entries[0].ID != "backup" {
+		t.Errorf("Expected backup job, got %+v (%v)", entries, err)
+	}
+	if !gounix.NewSystemdService("app", "/srv/app", "app").Enabled() {
+		t.Error("Expected enabled service")
+	}
+
+	// Remove rendered configs
+	if err := gounix.NewNginxReverseProxy("app", "8080").Runner(runner).Uninstall(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gounix.NewSystemdService("app", "/srv/app", "app").Runner(runner).Uninstall(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"/etc/nginx/sites-enabled/app", "/etc/systemd/system/multi-user.target.wants/app.service"} {
+		if _, err := fsys.Lstat(name); !os.IsNotExist(err) {
+			t.Errorf("Expected %s removed, got %v", name, err)
+		}
+	}
+}
+
+func TestRootFS(t *testing.T) {
+	root := t.TempDir()
+	gounix.SetFileSystem(gounix.NewRootFS(root))
+	defer gounix.SetFileSystem(nil)
+
+	site := gounix.NewNginxReverseProxy("app", "8080").Runner(gounix.NewRecordingRunner())
+	if _, err := site.Install(true); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := site.Enabled(); err != nil || !enabled {
+		t.Errorf("Expected enabled site, got %t (%v)", enabled, err)
+	}
+
+	// Links point to paths of target system
+	target, err := os.Readlink(filepath.Join(root, "etc/nginx/sites-enabled/app"))
+	if err != nil || target != "/etc/nginx/sites-available/app" {
+		t.Errorf("Expected %s, got %s (%v)", "/etc/nginx/sites-available/app", target, err)
+	} else {
+		t.Logf("Test passed on %s", target)
+	}
+}
+
+func TestRootFSLinks(t *testing.T) {
+	root, host := t.TempDir(), t.TempDir()
+	fsys := gounix.NewRootFS(root)
+
+	// Intermediate absolute link resolved inside root
+	if err := os.MkdirAll(filepath.Join(root, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(host, filepath.Join(root, "etc/nginx")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("/etc/nginx/sites-available/app", []byte("app"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Symlink("/etc/nginx/sites-available/app", "/etc/nginx/sites-enabled/app"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		filepath.Join(root, host, "sites-available/app"): true,
+		filepath.Join(root, host, "sites-enabled/app"):   true,
+		filepath.Join(host, "sites-available/app"):       false,
+		filepath.Join(host, "sites-enabled/app"):         false,
+	}
+	for name, expected := range tests {
+		_, err := os.Lstat(name)
+		if exists := err == nil; exists != expected {
+			t.Errorf("Expected %s exists %t, got %t", name, expected, exists)
+		} else {
+			t.Logf("Test passed on %s", name)
+		}
+	}
+
+	// Relative link resolved against link directory
+	if err := os.Symlink("nginx/sites-available", filepath.Join(root, "etc/sites")); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := fsys.ReadFile("/etc/sites/app"); err != nil || string(content) != "app" {
+		t.Errorf("Expected %s, got %s (%v)", "app", content, err)
+	}
+
+	// Final link not followed by remove
+	if err := fsys.Remove("/etc/nginx/sites-enabled/app"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("/etc/nginx/sites-available/app"); err != nil {
+		t.Errorf("Expected link target kept, got %v", err)
+	}
+}
+
+func TestStagingAtJob(t *testing.T) {
+	gounix.SetFileSystem(gounix.NewMemoryFS())
+	defer gounix.SetFileSystem(nil)
+
+	runner := gounix.NewRecordingRunner()
+	job := gounix.NewAtJob("echo hi", nil).Runner(runner).ID("app").Batch()
+	if _, err := job.Submit(); err == nil {
+		t.Errorf("Expected submit error, got nil")
+	}
+	if err := job.Cancel(); err == nil {
+		t.Errorf("Expected cancel error, got nil")
+	}
+	if commands := runner.Commands(); len(commands) != 0 {
+		t.Errorf("Expected no commands, got %v", commands)
+	}
+}
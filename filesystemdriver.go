@@ -0,0 +1,337 @@
+package gounix
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLinks maximum followed symbolic links of path.
+const maxLinks = 40
+
+type hostFS struct{}
+
+func (hostFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (hostFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (hostFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (hostFS) Symlink(target, link string) error {
+	return os.Symlink(target, link)
+}
+
+func (hostFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (hostFS) Lstat(name string) (fs.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+func (hostFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+type rootFS struct {
+	root string
+}
+
+func (r *rootFS) path(name string) string {
+	return filepath.Join(r.root, filepath.Clean("/"+name))
+}
+
+// resolve follows symbolic links of each component of name inside root,
+// so absolute links never escape to host. final component followed if follow set.
+func (r *rootFS) resolve(name string, follow bool) (string, error) {
+	parts := components(name)
+	resolved, links := "/", 0
+	for len(parts) > 0 {
+		next := filepath.Join(resolved, parts[0])
+		parts = parts[1:]
+		if len(parts) == 0 && !follow {
+			resolved = next
+			break
+		}
+
+		info, err := os.Lstat(r.path(next))
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		if links++; links > maxLinks {
+			return "", &fs.PathError{Op: "stat", Path: name, Err: errors.New("too many links")}
+		}
+
+		// Restart from root with link target and remaining components
+		target, err := os.Readlink(r.path(next))
+		if err != nil {
+			return "", err
+		} else if !filepath.IsAbs(target) {
+			target = filepath.Join(resolved, target)
+		}
+		parts = append(components(target), parts...)
+		resolved = "/"
+	}
+	return r.path(resolved), nil
+}
+
+// components splits cleaned absolute path into components.
+func components(name string) []string {
+	name = strings.TrimPrefix(filepath.Clean("/"+name), "/")
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, "/")
+}
+
+func (r *rootFS) ReadFile(name string) ([]byte, error) {
+	resolved, err := r.resolve(name, true)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(resolved)
+}
+
+func (r *rootFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	resolved, err := r.resolve(name, true)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(resolved), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(resolved, data, perm)
+}
+
+func (r *rootFS) Remove(name string) error {
+	resolved, err := r.resolve(name, false)
+	if err != nil {
+		return err
+	}
+	return os.Remove(resolved)
+}
+
+func (r *rootFS) Symlink(target, link string) error {
+	resolved, err := r.resolve(link, false)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(resolved), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(target, resolved)
+}
+
+func (r *rootFS) Stat(name string) (fs.FileInfo, error) {
+	resolved, err := r.resolve(name, true)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(resolved)
+}
+
+func (r *rootFS) Lstat(name string) (fs.FileInfo, error) {
+	resolved, err := r.resolve(name, false)
+	if err != nil {
+		return nil, err
+	}
+	return os.Lstat(resolved)
+}
+
+func (r *rootFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	resolved, err := r.resolve(name, true)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadDir(resolved)
+}
+
+type memoryFile struct {
+	data    []byte
+	mode    fs.FileMode
+	link    string
+	dir     bool
+	modTime time.Time
+}
+
+type memoryInfo struct {
+	name string
+	file *memoryFile
+}
+
+func (m memoryInfo) Name() string       { return m.name }
+func (m memoryInfo) Size() int64        { return int64(len(m.file.data)) }
+func (m memoryInfo) ModTime() time.Time { return m.file.modTime }
+func (m memoryInfo) IsDir() bool        { return m.file.dir }
+func (m memoryInfo) Sys() any           { return nil }
+
+func (m memoryInfo) Mode() fs.FileMode {
+	if m.file.dir {
+		return fs.ModeDir | 0755
+	} else if m.file.link != "" {
+		return fs.ModeSymlink | 0777
+	}
+	return m.file.mode
+}
+
+type memoryFS struct {
+	mutex sync.Mutex
+	files map[string]*memoryFile
+}
+
+// resolve follows symbolic links of name. must called with lock held.
+func (m *memoryFS) resolve(name string) (string, error) {
+	name = path.Clean("/" + name)
+	for i := 0; i < maxLinks; i++ {
+		file := m.files[name]
+		if file == nil || file.link == "" {
+			return name, nil
+		}
+		target := file.link
+		if !path.IsAbs(target) {
+			target = path.Join(path.Dir(name), target)
+		}
+		name = path.Clean(target)
+	}
+	return "", &fs.PathError{Op: "stat", Path: name, Err: errors.New("too many links")}
+}
+
+// mkdirs creates parent directories of name. must called with lock held.
+func (m *memoryFS) mkdirs(name string) error {
+	for dir := path.Dir(name); ; dir = path.Dir(dir) {
+		if file := m.files[dir]; file == nil {
+			m.files[dir] = &memoryFile{dir: true, modTime: time.Now()}
+		} else if !file.dir {
+			return &fs.PathError{Op: "mkdir", Path: dir, Err: errors.New("not a directory")}
+		}
+		if dir == "/" {
+			return nil
+		}
+	}
+}
+
+func (m *memoryFS) ReadFile(name string) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resolved, err := m.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	file := m.files[resolved]
+	if file == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	} else if file.dir {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errors.New("is a directory")}
+	}
+	return slices.Clone(file.data), nil
+}
+
+func (m *memoryFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resolved, err := m.resolve(name)
+	if err != nil {
+		return err
+	}
+	if file := m.files[resolved]; file != nil && file.dir {
+		return &fs.PathError{Op: "open", Path: name, Err: errors.New("is a directory")}
+	}
+	if err := m.mkdirs(resolved); err != nil {
+		return err
+	}
+	m.files[resolved] = &memoryFile{data: slices.Clone(data), mode: perm.Perm(), modTime: time.Now()}
+	return nil
+}
+
+func (m *memoryFS) Remove(name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	name = path.Clean("/" + name)
+	file := m.files[name]
+	if file == nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	} else if file.dir {
+		for child := range m.files {
+			if strings.HasPrefix(child, strings.TrimSuffix(name, "/")+"/") {
+				return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+			}
+		}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *memoryFS) Symlink(target, link string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	link = path.Clean("/" + link)
+	if m.files[link] != nil {
+		return &fs.PathError{Op: "symlink", Path: link, Err: fs.ErrExist}
+	}
+	if err := m.mkdirs(link); err != nil {
+		return err
+	}
+	m.files[link] = &memoryFile{link: target, modTime: time.Now()}
+	return nil
+}
+
+func (m *memoryFS) Stat(name string) (fs.FileInfo, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resolved, err := m.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	if file := m.files[resolved]; file != nil {
+		return memoryInfo{name: path.Base(resolved), file: file}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *memoryFS) Lstat(name string) (fs.FileInfo, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	name = path.Clean("/" + name)
+	if file := m.files[name]; file != nil {
+		return memoryInfo{name: path.Base(name), file: file}, nil
+	}
+	return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *memoryFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	resolved, err := m.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	if file := m.files[resolved]; file == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	} else if !file.dir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	result := make([]fs.DirEntry, 0)
+	for child, file := range m.files {
+		if child != resolved && path.Dir(child) == resolved {
+			result = append(result, fs.FileInfoToDirEntry(memoryInfo{name: path.Base(child), file: file}))
+		}
+	}
+	slices.SortFunc(result, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return result, nil
+}
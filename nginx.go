@@ -11,6 +11,8 @@ type ServerBlock interface {
 	// Template sets the template for the site.
 	// template string can contain {domains} and {port} placeholders.
 	Template(engine TemplateEngine) ServerBlock
+	// Runner sets command runner of the site (default runner if nil).
+	Runner(runner Runner) ServerBlock
 	// Disable disables the site manually.
 	Disable() error
 	// Enable enables the site manually.
@@ -1,16 +1,13 @@
 package gounix
 
-import (
-	"os"
-	"os/exec"
-	"strings"
-)
+import "strings"
 
 type nginxReverseProxy struct {
 	name     string
 	port     string
 	domains  []string
 	template TemplateEngine
+	runner   Runner
 }
 
 func (n *nginxReverseProxy) path() string {
@@ -41,22 +38,33 @@ func (n *nginxReverseProxy) Template(engine TemplateEngine) ServerBlock {
 	return n
 }
 
+func (n *nginxReverseProxy) Runner(runner Runner) ServerBlock {
+	n.runner = runner
+	return n
+}
+
 func (n *nginxReverseProxy) Disable() error {
-	// Delete link
-	err := os.Remove(n.link())
-	if os.IsNotExist(err) {
+	// Skip if not exists
+	exists, err := linkExists(n.runner, n.link())
+	if err != nil {
+		return err
+	} else if !exists {
 		return nil
-	} else if err != nil {
+	}
+
+	// Delete link
+	err = removeFile(n.runner, n.link())
+	if err != nil {
 		return err
 	}
 
 	// Restart nginx to apply the changes
-	return cmdError(exec.Command("sudo", "systemctl", "restart", "nginx").Run())
+	return runLive(n.runner, "systemctl", "restart", "nginx")
 }
 
 func (n *nginxReverseProxy) Enable() error {
 	// Skip if exists
-	exists, err := fileExists(n.link())
+	exists, err := fileExists(n.runner, n.link())
 	if err != nil {
 		return err
 	} else if exists {
@@ -64,26 +72,26 @@ func (n *nginxReverseProxy) Enable() error {
 	}
 
 	// Create link
-	err = os.Symlink(n.path(), n.link())
+	err = symlink(n.runner, n.path(), n.link())
 	if err != nil {
 		return err
 	}
 
 	// Restart nginx to apply the changes
-	return cmdError(exec.Command("sudo", "systemctl", "restart", "nginx").Run())
+	return runLive(n.runner, "systemctl", "restart", "nginx")
 }
 
 func (n *nginxReverseProxy) Exists() (bool, error) {
-	return fileExists(n.path())
+	return fileExists(n.runner, n.path())
 }
 
 func (n *nginxReverseProxy) Enabled() (bool, error) {
-	available, err := fileExists(n.path())
+	available, err := fileExists(n.runner, n.path())
 	if err != nil {
 		return false, err
 	}
 
-	enabled, err := fileExists(n.link())
+	enabled, err := fileExists(n.runner, n.link())
 	if err != nil {
 		return false, err
 	}
@@ -93,7 +101,7 @@ func (n *nginxReverseProxy) Enabled() (bool, error) {
 
 func (n *nginxReverseProxy) Install(override bool) (bool, error) {
 	// Check exists and override
-	exists, err := fileExists(n.path())
+	exists, err := fileExists(n.runner, n.path())
 	if err != nil {
 		return false, err
 	} else if exists && !override {
@@ -109,7 +117,7 @@ func (n *nginxReverseProxy) Install(override bool) (bool, error) {
 	)
 
 	// Create server file
-	err = os.WriteFile(n.path(), content, 0644)
+	err = writeFile(n.runner, n.path(), content, 0644)
 	if err != nil {
 		return false, err
 	}
@@ -121,7 +129,7 @@ func (n *nginxReverseProxy) Install(override bool) (bool, error) {
 	}
 
 	// Restart nginx to apply the changes
-	err = cmdError(exec.Command("sudo", "systemctl", "restart", "nginx").Run())
+	err = runLive(n.runner, "systemctl", "restart", "nginx")
 	if err != nil {
 		return false, err
 	}
@@ -131,17 +139,17 @@ func (n *nginxReverseProxy) Install(override bool) (bool, error) {
 
 func (n *nginxReverseProxy) Uninstall() error {
 	// Remove the enabled site link
-	err := os.Remove(n.link())
-	if err != nil && !os.IsNotExist(err) {
+	err := removeFile(n.runner, n.link())
+	if err != nil {
 		return err
 	}
 
 	// Remove the available site file
-	err = os.Remove(n.path())
-	if err != nil && !os.IsNotExist(err) {
+	err = removeFile(n.runner, n.path())
+	if err != nil {
 		return err
 	}
 
 	// Restart nginx to apply the changes
-	return cmdError(exec.Command("sudo", "systemctl", "restart", "nginx").Run())
+	return runLive(n.runner, "systemctl", "restart", "nginx")
 }
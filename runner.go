@@ -0,0 +1,47 @@
+package gounix
+
+import "context"
+
+// RunCommand represents command executed by runner.
+type RunCommand struct {
+	Args  []string // program and arguments (privileged commands prefixed by escalation policy)
+	Stdin string
+	Env   []string // additional KEY=value environment variables
+}
+
+// RunResult represents result of executed command.
+type RunResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Runner executes commands of drivers. returns error only if command
+// can not be executed, non-zero exit code reported in result.
+type Runner interface {
+	Run(ctx context.Context, cmd RunCommand) (RunResult, error)
+}
+
+// defaultRunner runner of package functions and drivers without runner.
+var defaultRunner Runner = NewLocalRunner()
+
+// SetRunner sets default runner of package functions and drivers
+// without runner option. nil means local runner.
+func SetRunner(runner Runner) {
+	if runner == nil {
+		runner = NewLocalRunner()
+	}
+	defaultRunner = runner
+}
+
+// NewLocalRunner creates runner executing commands on local machine.
+func NewLocalRunner() Runner {
+	return new(localRunner)
+}
+
+// NewRecordingRunner creates fake runner recording executed commands
+// without executing them. commands succeed with empty output unless
+// reply configured.
+func NewRecordingRunner() *RecordingRunner {
+	return new(RecordingRunner)
+}
@@ -0,0 +1,250 @@
+package gounix_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mekramy/gounix"
+)
+
+func TestRecordingRunnerCron(t *testing.T) {
+	runner := gounix.NewRecordingRunner().
+		Reply("sudo -n crontab -u deploy -l", gounix.RunResult{Stdout: "0 2 * * * backup\n"})
+	job := gounix.NewCronJob("report", nil).ID("report").Daily().SetHour(3).User("deploy").Runner(runner)
+
+	if exists, err := job.Exists(); err != nil || exists {
+		t.Errorf("Expected missing job, got %t (%v)", exists, err)
+	}
+	if _, err := job.Install(); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := runner.Calls()
+	last := calls[len(calls)-1]
+	expected := "0 2 * * * backup\n# gounix:id=report\n0 3 * * * report\n"
+	if strings.Join(last.Args, " ") != "sudo -n crontab -u deploy -" {
+		t.Errorf("Expected %s, got %s", "sudo -n crontab -u deploy -", strings.Join(last.Args, " "))
+	} else if last.Stdin != expected {
+		t.Errorf("Expected %q, got %q", expected, last.Stdin)
+	} else {
+		t.Logf("Test passed on %q", last.Stdin)
+	}
+
+	// Missing crontab read as empty
+	runner = gounix.NewRecordingRunner().
+		Reply("sudo -n crontab -l", gounix.RunResult{ExitCode: 1, Stderr: "no crontab for root"})
+	if exists, err := gounix.NewCronJob("report", nil).Runner(runner).Exists(); err != nil || exists {
+		t.Errorf("Expected missing job on empty crontab, got %t (%v)", exists, err)
+	}
+
+	// Non-zero exit code returned as error
+	runner = gounix.NewRecordingRunner().
+		Reply("sudo -n crontab -l", gounix.RunResult{ExitCode: 1, Stderr: "permission denied"})
+	if _, err := gounix.NewCronJob("report", nil).Runner(runner).Exists(); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected exit error, got %v", err)
+	}
+}
+
+func TestRecordingRunnerCommands(t *testing.T) {
+	runner := gounix.NewRecordingRunner().
+		Reply("sudo -n systemctl is-enabled", gounix.RunResult{Stdout: "enabled\n"}).
+		Reply("sudo -n systemctl status", gounix.RunResult{ExitCode: 3}).
+		Reply("sudo -n batch", gounix.RunResult{Stderr: "job 7 at Sat Oct 17 03:00:00 2026\n"})
+
+	service := gounix.NewSystemdService("api", "/srv/api", "api").Runner(runner)
+	if !service.Enabled() {
+		t.Error("Expected enabled service")
+	}
+	if service.Exists() {
+		t.Error("Expected missing service on non-zero status")
+	}
+	if number, err := gounix.NewAtJob("migrate", nil).Batch().Runner(runner).Submit(); err != nil || number != 7 {
+		t.Errorf("Expected at job 7, got %d (%v)", number, err)
+	}
+
+	tests := map[string]func(runner *gounix.RecordingRunner){
+		"sudo -n systemctl is-enabled report.timer": func(runner *gounix.RecordingRunner) {
+			gounix.NewCronJob("report", nil).Daily().ToSystemdTimer("report").Runner(runner).Enabled()
+		},
+		"sudo -n systemctl disable gounix-runner-test": func(runner *gounix.RecordingRunner) {
+			gounix.NewSystemdService("gounix-runner-test", "/srv/api", "api").Runner(runner).Uninstall()
+		},
+		"sudo -n systemctl restart nginx": func(runner *gounix.RecordingRunner) {
+			gounix.NewNginxReverseProxy("gounix-runner-test", "8080").Runner(runner).Uninstall()
+		},
+		"sudo -n atrm 5": func(runner *gounix.RecordingRunner) {
+			runner.
+				Reply("sudo -n atq", gounix.RunResult{Stdout: "5\tSat Oct 17 03:00:00 2026 a root\n6\tSat Oct 17 04:00:00 2026 a root\n"}).
+				Reply("sudo -n at -c 5", gounix.RunResult{Stdout: "# gounix:id=migrate\nmigrate\n"}).
+				Fail("sudo -n atrm", errors.New("atrm not found"))
+			if err := gounix.NewAtJob("migrate", nil).ID("migrate").Runner(runner).Cancel(); err == nil {
+				t.Error("Expected execution error")
+			}
+		},
+	}
+	for expected, call := range tests {
+		runner := gounix.NewRecordingRunner()
+		call(runner)
+		if commands := runner.Commands(); !slices.Contains(commands, expected) || slices.Contains(commands, "sudo -n atrm 6") {
+			t.Errorf("Expected %s, got %v", expected, commands)
+		} else {
+			t.Logf("Test passed on %s", expected)
+		}
+	}
+}
+
+func TestRecordingRunnerFiles(t *testing.T) {
+	gounix.SetEscalation(gounix.EscalationNone)
+	defer gounix.SetEscalation(gounix.EscalationSudo)
+	gounix.SetCronRestart(gounix.CronRestartNever)
+	defer gounix.SetCronRestart(gounix.CronRestartAuto)
+
+	// Read-modify-write of remote file through runner
+	runner := gounix.NewRecordingRunner().
+		Reply("cat /etc/cron.d/app", gounix.RunResult{Stdout: "0 1 * * * root sync\n"})
+	job := gounix.NewCronJob("report", nil).Daily().Store(gounix.NewCronDFile("app")).Runner(runner)
+	if _, err := job.Install(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "0 1 * * * root sync\n0 0 * * * root report\n"
+	for _, call := range runner.Calls() {
+		if strings.Join(call.Args, " ") == "tee /etc/cron.d/app" {
+			if call.Stdin != expected {
+				t.Errorf("Expected %q, got %q", expected, call.Stdin)
+			} else {
+				t.Logf("Test passed on %q", call.Stdin)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected write through runner, got %v", runner.Commands())
+}
+
+func TestRecordingRunnerBatch(t *testing.T) {
+	gounix.SetCronRestart(gounix.CronRestartNever)
+	defer gounix.SetCronRestart(gounix.CronRestartAuto)
+
+	// Batch writes cron.d file through runner
+	runner := gounix.NewRecordingRunner()
+	_, err := gounix.NewCronBatch(gounix.NewCronDFile("app")).
+		Runner(runner).
+		Install(gounix.NewCronJob("report", nil).Daily()).
+		Apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, call := range runner.Calls() {
+		if strings.Join(call.Args, " ") == "sudo -n tee /etc/cron.d/app" {
+			if expected := "0 0 * * * root report\n"; call.Stdin != expected {
+				t.Errorf("Expected %q, got %q", expected, call.Stdin)
+			} else {
+				t.Logf("Test passed on %q", call.Stdin)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected write through runner, got %v", runner.Commands())
+}
+
+func TestRecordingRunnerListCron(t *testing.T) {
+	runner := gounix.NewRecordingRunner().
+		Reply("sudo -n find /var/spool/cron/crontabs", gounix.RunResult{Stdout: "alice\nbob\n"}).
+		Reply("sudo -n crontab -u alice -l", gounix.RunResult{Stdout: "0 2 * * * backup\n"}).
+		Reply("sudo -n crontab -u bob -l", gounix.RunResult{ExitCode: 1, Stderr: "no crontab for bob"})
+	entries, err := gounix.ListCronJobs(gounix.CronListOptions{AllUsers: true, Runner: runner})
+	if err != nil || len(entries) != 1 || entries[0].Owner != "alice" {
+		t.Errorf("Expected backup job of alice, got %+v (%v)", entries, err)
+	}
+	expected := "sudo -n find /var/spool/cron/crontabs -mindepth 1 -maxdepth 1 -type f -exec basename {} ;"
+	if commands := runner.Commands(); !slices.Contains(commands, expected) {
+		t.Errorf("Expected portable %s, got %v", expected, commands)
+	}
+
+	// Denied escalation of root-only spool
+	runner = gounix.NewRecordingRunner().
+		Reply("sudo -n find", gounix.RunResult{ExitCode: 1, Stderr: "sudo: a password is required"})
+	if _, err := gounix.ListCronJobs(gounix.CronListOptions{AllUsers: true, Runner: runner}); !errors.Is(err, gounix.ErrNoPrivileges) {
+		t.Errorf("Expected ErrNoPrivileges, got %v", err)
+	}
+}
+
+func TestRecordingRunnerListWrapped(t *testing.T) {
+	calendar := gounix.NewCronCalendar().Recurring(time.January, 1)
+	jobs := map[string]gounix.CronJob{
+		"backup --db 'main'":  gounix.NewCronJob("backup --db 'main'", nil).ID("db").NoOverlap().Timeout(time.Hour),
+		"backup --logs":       gounix.NewCronJob("backup --logs", nil).ID("logs").LogFile("/var/log/backup.log").RecordHistory(""),
+		"backup --full 100%":  gounix.NewCronJob("backup --full 100%", nil).ID("full").Exclude(calendar),
+		"backup --home it's":  gounix.NewCronJob("backup --home it's", nil).ID("home").Exclude(calendar).Env("LANG", "C"),
+		"cleanup --no-backup": gounix.NewCronJob("cleanup --no-backup", nil).ID("cleanup").NoOverlap(),
+	}
+	tab := gounix.ParseCrontab("")
+	for _, job := range jobs {
+		if _, err := tab.Set(job.Daily()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Wrapped jobs listed by user command
+	runner := gounix.NewRecordingRunner().
+		Reply("sudo -n crontab -u deploy -l", gounix.RunResult{Stdout: tab.String()})
+	entries, err := gounix.ListCronJobs(gounix.CronListOptions{Users: []string{"deploy"}, CommandPrefix: "backup", Runner: runner})
+	if err != nil || len(entries) != 4 {
+		t.Fatalf("Expected 4 backup jobs, got %+v (%v)", entries, err)
+	}
+	for _, entry := range entries {
+		if _, ok := jobs[entry.Command]; !ok {
+			t.Errorf("Expected user command, got %s", entry.Command)
+		} else {
+			t.Logf("Test passed on %s", entry.Command)
+		}
+	}
+}
+
+func TestRecordingRunnerCronDaemon(t *testing.T) {
+	gounix.SetCronRestart(gounix.CronRestartAlways)
+	defer gounix.SetCronRestart(gounix.CronRestartAuto)
+	busybox := func() *gounix.RecordingRunner {
+		return gounix.NewRecordingRunner().
+			Reply("test -d /run/systemd/system", gounix.RunResult{ExitCode: 1}).
+			Reply("sh -c command -v 'rc-service'", gounix.RunResult{Stdout: "/sbin/rc-service\n"}).
+			Reply("sh -c command -v 'crond'", gounix.RunResult{Stdout: "/usr/sbin/crond\n"}).
+			Reply("readlink -f /usr/sbin/crond", gounix.RunResult{Stdout: "/bin/busybox\n"})
+	}
+
+	// Daemon and init system detected on runner host, sbin
+	// directories checked outside of PATH
+	tests := map[string]struct {
+		runner *gounix.RecordingRunner
+		store  gounix.CronStore
+	}{
+		"sudo -n systemctl restart cron": {
+			gounix.NewRecordingRunner().
+				Reply("test -x", gounix.RunResult{ExitCode: 1}).
+				Reply("test -x /usr/sbin/cron", gounix.RunResult{}),
+			gounix.NewCronDFile("app"),
+		},
+		"sudo -n rc-service crond restart": {busybox(), nil},
+	}
+	for expected, test := range tests {
+		job := gounix.NewCronJob("report", nil).Daily().Store(test.store).Runner(test.runner)
+		if _, err := job.Install(); err != nil {
+			t.Fatal(err)
+		}
+		if commands := test.runner.Commands(); !slices.Contains(commands, expected) {
+			t.Errorf("Expected %s, got %v", expected, commands)
+		} else {
+			t.Logf("Test passed on %s", expected)
+		}
+	}
+
+	// Busybox crond does not read cron.d files
+	runner := busybox()
+	job := gounix.NewCronJob("report", nil).Daily().Store(gounix.NewCronDFile("app")).Runner(runner)
+	if _, err := job.Install(); err == nil || slices.Contains(runner.Commands(), "sudo -n rc-service crond restart") {
+		t.Errorf("Expected unsupported store error, got %v (%v)", err, runner.Commands())
+	}
+}
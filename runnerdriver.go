@@ -0,0 +1,129 @@
+package gounix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+type localRunner struct{}
+
+func (localRunner) Run(ctx context.Context, cmd RunCommand) (RunResult, error) {
+	if len(cmd.Args) == 0 {
+		return RunResult{}, errors.New("empty command")
+	}
+
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, cmd.Args[0], cmd.Args[1:]...)
+	command.Stdin = strings.NewReader(cmd.Stdin)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if len(cmd.Env) > 0 {
+		command.Env = append(os.Environ(), cmd.Env...)
+	}
+
+	err := command.Run()
+	result := RunResult{Stdout: stdout.String(), Stderr: stderr.String()}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+		return result, nil
+	}
+	return result, err
+}
+
+// RecordingRunner fake runner recording executed commands.
+type RecordingRunner struct {
+	mutex   sync.Mutex
+	calls   []RunCommand
+	replies []recordingReply
+}
+
+type recordingReply struct {
+	prefix string
+	result RunResult
+	err    error
+}
+
+// Reply sets result of commands starting with prefix (space separated
+// arguments, e.g. "sudo crontab -l"). longest matching prefix used.
+func (r *RecordingRunner) Reply(prefix string, result RunResult) *RecordingRunner {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.replies = append(r.replies, recordingReply{prefix: prefix, result: result})
+	return r
+}
+
+// Fail sets execution error of commands starting with prefix.
+func (r *RecordingRunner) Fail(prefix string, err error) *RecordingRunner {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.replies = append(r.replies, recordingReply{prefix: prefix, err: err})
+	return r
+}
+
+// Calls returns recorded commands.
+func (r *RecordingRunner) Calls() []RunCommand {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]RunCommand(nil), r.calls...)
+}
+
+// Commands returns recorded commands as space separated arguments.
+func (r *RecordingRunner) Commands() []string {
+	result := make([]string, 0)
+	for _, call := range r.Calls() {
+		result = append(result, strings.Join(call.Args, " "))
+	}
+	return result
+}
+
+// Reset clears recorded commands.
+func (r *RecordingRunner) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.calls = nil
+}
+
+func (r *RecordingRunner) Run(ctx context.Context, cmd RunCommand) (RunResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.calls = append(r.calls, cmd)
+
+	// Find longest matching reply
+	command := strings.Join(cmd.Args, " ")
+	var reply *recordingReply
+	for i, item := range r.replies {
+		if (command == item.prefix || strings.HasPrefix(command, item.prefix+" ")) &&
+			(reply == nil || len(item.prefix) >= len(reply.prefix)) {
+			reply = &r.replies[i]
+		}
+	}
+	if reply == nil {
+		return RunResult{}, ctx.Err()
+	}
+	return reply.result, reply.err
+}
+
+// runnerOf get runner or default runner if nil.
+func runnerOf(runner Runner) Runner {
+	if runner != nil {
+		return runner
+	}
+	return defaultRunner
+}
+
+// run runs command with stdin. non-zero exit code returned as error.
+func run(runner Runner, stdin string, args ...string) (RunResult, error) {
+	result, err := runnerOf(runner).Run(context.Background(), RunCommand{Args: args, Stdin: stdin})
+	if err != nil {
+		return result, err
+	} else if result.ExitCode != 0 {
+		return result, fmt.Errorf("Exit %d, %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package gounix
+
+import (
+	"context"
+	"time"
+)
+
+// OverlapPolicy determines how scheduler handles run time of job
+// while previous run still running.
+type OverlapPolicy int
+
+const (
+	OverlapSkip     OverlapPolicy = 0 // skip run
+	OverlapQueue    OverlapPolicy = 1 // run after previous runs finished
+	OverlapParallel OverlapPolicy = 2 // run in parallel
+)
+
+// SchedulerHandler handler of scheduled job. context canceled
+// when scheduler stop deadline exceeded.
+type SchedulerHandler func(ctx context.Context) error
+
+// SchedulerHooks hooks called on job runs. nil hooks ignored.
+type SchedulerHooks struct {
+	OnStart  func(name string, at time.Time)
+	OnFinish func(name string, at time.Time, duration time.Duration, err error)
+	OnSkip   func(name string, at time.Time)
+}
+
+// Scheduler in-process scheduler running handlers on cron job schedules
+// (including timezone and weekend semantics) without cron daemon.
+type Scheduler interface {
+	// Add adds job with unique name. job schedule validated and
+	// @reboot jobs run once on start. handler panics recovered as errors.
+	Add(name string, job CronJob, policy OverlapPolicy, handler SchedulerHandler) error
+	// Remove removes job from scheduler. running handlers not interrupted.
+	Remove(name string) bool
+	// Hooks sets run hooks.
+	Hooks(hooks SchedulerHooks) Scheduler
+	// Next returns next run time of job.
+	Next(name string) (time.Time, bool)
+	// Start starts scheduling jobs in background.
+	Start()
+	// Stop stops scheduling and waits running handlers to finish.
+	// handlers context canceled and ctx error returned if ctx done first.
+	Stop(ctx context.Context) error
+}
+
+// NewScheduler creates in-process scheduler.
+func NewScheduler() Scheduler {
+	scheduler := new(schedulerDriver)
+	scheduler.jobs = make(map[string]*schedulerJob)
+	return scheduler
+}
@@ -0,0 +1,115 @@
+package gounix_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mekramy/gounix"
+)
+
+func TestSchedulerRebootJobs(t *testing.T) {
+	var mutex sync.Mutex
+	finished := make(map[string]error)
+	done := make(chan struct{}, 2)
+	scheduler := gounix.NewScheduler().Hooks(gounix.SchedulerHooks{
+		OnFinish: func(name string, at time.Time, duration time.Duration, err error) {
+			mutex.Lock()
+			finished[name] = err
+			mutex.Unlock()
+			done <- struct{}{}
+		},
+	})
+
+	reboot := gounix.NewCronJob("in-process", nil).AtReboot()
+	scheduler.Add("ok", reboot, gounix.OverlapSkip, func(ctx context.Context) error { return nil })
+	scheduler.Add("panic", reboot, gounix.OverlapQueue, func(ctx context.Context) error { panic("boom") })
+	if err := scheduler.Add("ok", reboot, gounix.OverlapSkip, func(ctx context.Context) error { return nil }); err == nil {
+		t.Error("Expected error on duplicate job name")
+	}
+	if err := scheduler.Add("invalid", gounix.NewCronJob("x", nil).SetMinute(75), gounix.OverlapSkip, func(ctx context.Context) error { return nil }); err == nil {
+		t.Error("Expected error on invalid job")
+	}
+
+	scheduler.Add("minute", gounix.NewCronJob("in-process", nil), gounix.OverlapSkip, func(ctx context.Context) error { return nil })
+	if next, ok := scheduler.Next("minute"); !ok || time.Until(next) > time.Minute {
+		t.Errorf("Expected next run in one minute, got %s", next)
+	}
+
+	scheduler.Start()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Expected reboot jobs run on start")
+		}
+	}
+	if err := scheduler.Stop(context.Background()); err != nil {
+		t.Errorf("Expected graceful stop, got %v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if err, ok := finished["ok"]; !ok || err != nil {
+		t.Errorf("Expected ok job finished, got %v", err)
+	}
+	if err := finished["panic"]; err == nil {
+		t.Error("Expected panic recovered as error")
+	}
+}
+
+func TestSchedulerStopTimeout(t *testing.T) {
+	started := make(chan struct{})
+	scheduler := gounix.NewScheduler()
+	scheduler.Add("slow", gounix.NewCronJob("in-process", nil).AtReboot(), gounix.OverlapParallel, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	scheduler.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := scheduler.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if next, ok := scheduler.Next("slow"); ok {
+		t.Errorf("Expected no next run of reboot job, got %s", next)
+	}
+}
+
+func TestSchedulerTimezone(t *testing.T) {
+	handler := func(ctx context.Context) error { return nil }
+	scheduler := gounix.NewScheduler()
+	defer scheduler.Stop(context.Background())
+
+	// Untranslatable crontab schedule evaluated in job timezone
+	now := time.Now()
+	_, local := now.Zone()
+	zones := map[string]*time.Location{
+		"offset": time.FixedZone("", local+3*3600+30*60),
+	}
+	jobs := map[string]gounix.CronJob{
+		"offset": gounix.NewCronJob("", gounix.NewTZ().Hour(3).Minute(30)).Monthly(),
+	}
+	if tehran, err := time.LoadLocation("Asia/Tehran"); err == nil {
+		zones["location"] = tehran
+		jobs["location"] = gounix.NewCronJob("", gounix.NewTZFromLocation(tehran)).Monthly()
+	}
+
+	for name, job := range jobs {
+		if err := scheduler.Add(name, job, gounix.OverlapSkip, handler); err != nil {
+			t.Errorf("Expected %s job added, got %v", name, err)
+			continue
+		}
+		next, ok := scheduler.Next(name)
+		if at := next.In(zones[name]); !ok || at.Day() != 1 || at.Hour() != 0 || at.Minute() != 0 {
+			t.Errorf("Expected %s job on first day of month, got %s", name, at)
+		} else {
+			t.Logf("Test passed on %s", name)
+		}
+	}
+}
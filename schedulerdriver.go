@@ -0,0 +1,284 @@
+package gounix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// schedulerQueueSize maximum queued runs of job with queue policy.
+const schedulerQueueSize = 64
+
+type schedulerJob struct {
+	name    string
+	job     CronJob
+	policy  OverlapPolicy
+	handler SchedulerHandler
+	next    time.Time
+	running atomic.Int32
+	queue   chan time.Time
+	stop    chan struct{}
+}
+
+type schedulerDriver struct {
+	mutex   sync.Mutex
+	jobs    map[string]*schedulerJob
+	hooks   SchedulerHooks
+	started bool
+	stopped bool
+	ctx     context.Context // handlers context
+	cancel  context.CancelFunc
+	loops   sync.WaitGroup // scheduling loops
+	runs    sync.WaitGroup // running handlers
+}
+
+func (s *schedulerDriver) Add(name string, job CronJob, policy OverlapPolicy, handler SchedulerHandler) error {
+	if job == nil || handler == nil {
+		return errors.New("nil scheduler job or handler")
+	} else if policy < OverlapSkip || policy > OverlapParallel {
+		return fmt.Errorf("invalid overlap policy %d", policy)
+	}
+	if driver, ok := job.(*cronDriver); ok {
+		// Schedule evaluated in process, crontab entry not validated
+		if err := driver.validateSchedule(nil, false); err != nil {
+			return err
+		}
+	} else if err := job.Validate(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("scheduler job %q already exists", name)
+	} else if s.stopped {
+		return errors.New("scheduler stopped")
+	}
+
+	j := &schedulerJob{
+		name:    name,
+		job:     job,
+		policy:  policy,
+		handler: handler,
+		stop:    make(chan struct{}),
+	}
+	if policy == OverlapQueue {
+		j.queue = make(chan time.Time, schedulerQueueSize)
+	}
+	s.jobs[name] = j
+	if s.started {
+		s.schedule(j)
+	}
+	return nil
+}
+
+func (s *schedulerDriver) Remove(name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	j, ok := s.jobs[name]
+	if ok && !s.stopped {
+		close(j.stop)
+	}
+	delete(s.jobs, name)
+	return ok
+}
+
+func (s *schedulerDriver) Hooks(hooks SchedulerHooks) Scheduler {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.hooks = hooks
+	return s
+}
+
+func (s *schedulerDriver) Next(name string) (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if j, ok := s.jobs[name]; ok && !j.next.IsZero() {
+		return j.next, true
+	} else if ok {
+		next, err := nextRun(j.job, time.Now())
+		return next, err == nil
+	}
+	return time.Time{}, false
+}
+
+func (s *schedulerDriver) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, j := range s.jobs {
+		s.schedule(j)
+	}
+}
+
+func (s *schedulerDriver) Stop(ctx context.Context) error {
+	s.mutex.Lock()
+	if s.stopped {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.stopped = true
+	for _, j := range s.jobs {
+		close(j.stop)
+	}
+	started := s.started
+	s.mutex.Unlock()
+	if !started {
+		return nil
+	}
+
+	// Wait for loops and running handlers
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		s.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// schedule starts scheduling loop of job. must called with lock held.
+func (s *schedulerDriver) schedule(j *schedulerJob) {
+	s.loops.Add(1)
+	if j.queue != nil {
+		s.loops.Add(1)
+		go s.worker(j)
+	}
+	go s.loop(j)
+}
+
+// loop waits for run times of job and dispatches runs.
+func (s *schedulerDriver) loop(j *schedulerJob) {
+	defer s.loops.Done()
+	if j.queue != nil {
+		defer close(j.queue)
+	}
+
+	// Reboot jobs run once on start
+	after := time.Now()
+	if _, err := nextRun(j.job, after); errors.Is(err, ErrNoRunTime) {
+		if driver, ok := j.job.(*cronDriver); ok && driver.reboot {
+			s.dispatch(j, after)
+		}
+		return
+	}
+
+	for {
+		next, err := nextRun(j.job, after)
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		j.next = next
+		s.mutex.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-j.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.dispatch(j, next)
+
+		// Skip run times missed while system suspended
+		after = next
+		if now := time.Now(); now.Sub(next) > time.Minute {
+			after = now
+		}
+	}
+}
+
+// worker runs queued runs of job one by one.
+func (s *schedulerDriver) worker(j *schedulerJob) {
+	defer s.loops.Done()
+	for at := range j.queue {
+		j.running.Add(1)
+		s.run(j, at)
+		j.running.Add(-1)
+	}
+}
+
+// dispatch runs job according to overlap policy.
+func (s *schedulerDriver) dispatch(j *schedulerJob, at time.Time) {
+	switch j.policy {
+	case OverlapQueue:
+		select {
+		case j.queue <- at:
+		default:
+			s.skip(j, at)
+		}
+		return
+	case OverlapParallel:
+		j.running.Add(1)
+	default:
+		if !j.running.CompareAndSwap(0, 1) {
+			s.skip(j, at)
+			return
+		}
+	}
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		defer j.running.Add(-1)
+		s.run(j, at)
+	}()
+}
+
+// skip reports skipped run.
+func (s *schedulerDriver) skip(j *schedulerJob, at time.Time) {
+	s.mutex.Lock()
+	hook := s.hooks.OnSkip
+	s.mutex.Unlock()
+	if hook != nil {
+		hook(j.name, at)
+	}
+}
+
+// run runs job handler with panic recovery and hooks.
+func (s *schedulerDriver) run(j *schedulerJob, at time.Time) {
+	s.mutex.Lock()
+	hooks := s.hooks
+	s.mutex.Unlock()
+	if hooks.OnStart != nil {
+		hooks.OnStart(j.name, at)
+	}
+
+	start := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("scheduler job %q panic: %v", j.name, r)
+			}
+		}()
+		return j.handler(s.ctx)
+	}()
+
+	if hooks.OnFinish != nil {
+		hooks.OnFinish(j.name, at, time.Since(start), err)
+	}
+}
+
+// nextRun get next run time of job in job timezone without
+// daemon timezone translation.
+func nextRun(job CronJob, after time.Time) (time.Time, error) {
+	if driver, ok := job.(*cronDriver); ok {
+		return driver.localNext(after)
+	}
+	return job.NextRun(after)
+}
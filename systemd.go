@@ -11,6 +11,8 @@ type SystemdService interface {
 	// Template sets the template for the service.
 	// template string can contain {name}, {root} and {command} placeholders.
 	Template(engine TemplateEngine) SystemdService
+	// Runner sets command runner of the service (default runner if nil).
+	Runner(runner Runner) SystemdService
 	// Exists checks if the service exists.
 	Exists() bool
 	// Enabled checks if the service exists and enabled on startup.
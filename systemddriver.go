@@ -1,16 +1,13 @@
 package gounix
 
-import (
-	"os"
-	"os/exec"
-	"strings"
-)
+import "strings"
 
 type systemdDriver struct {
 	name     string
 	root     string
 	command  string
 	template TemplateEngine
+	runner   Runner
 }
 
 func (s systemdDriver) path() string {
@@ -37,14 +34,32 @@ func (s *systemdDriver) Template(engine TemplateEngine) SystemdService {
 	return s
 }
 
+func (s *systemdDriver) Runner(runner Runner) SystemdService {
+	s.runner = runner
+	return s
+}
+
+// link get enable link of the service (staging filesystems).
+func (s systemdDriver) link() string {
+	return wantsLink("multi-user.target", s.name+".service")
+}
+
 func (s *systemdDriver) Exists() bool {
-	_, err := exec.Command("sudo", "systemctl", "status", s.name).Output()
+	if staging() {
+		exists, _ := fileExists(s.runner, s.path())
+		return exists
+	}
+	_, err := runRoot(s.runner, "", "systemctl", "status", s.name)
 	return err == nil
 }
 
 func (s *systemdDriver) Enabled() bool {
-	output, _ := exec.Command("sudo", "systemctl", "is-enabled", s.name).Output()
-	return strings.HasPrefix(string(output), "enabled")
+	if staging() {
+		enabled, _ := linkExists(s.runner, s.link())
+		return enabled
+	}
+	result, _ := runRoot(s.runner, "", "systemctl", "is-enabled", s.name)
+	return strings.HasPrefix(result.Stdout, "enabled")
 }
 
 func (s *systemdDriver) Install(override bool) (bool, error) {
@@ -64,25 +79,29 @@ func (s *systemdDriver) Install(override bool) (bool, error) {
 	)
 
 	// Create service file
-	err := os.WriteFile(s.path(), []byte(content), 0644)
+	err := writeFile(s.runner, s.path(), []byte(content), 0644)
 	if err != nil {
 		return false, err
 	}
 
 	// Reload services
-	err = cmdError(exec.Command("sudo", "systemctl", "daemon-reload").Run())
+	err = runLive(s.runner, "systemctl", "daemon-reload")
 	if err != nil {
 		return false, err
 	}
 
 	// Enable service on startup
-	err = cmdError(exec.Command("sudo", "systemctl", "enable", s.name).Run())
+	if staging() {
+		err = enableLink(s.runner, s.path(), s.link())
+	} else {
+		_, err = runRoot(s.runner, "", "systemctl", "enable", s.name)
+	}
 	if err != nil {
 		return false, err
 	}
 
 	// Start service
-	err = cmdError(exec.Command("sudo", "systemctl", "start", s.name).Run())
+	err = runLive(s.runner, "systemctl", "start", s.name)
 	if err != nil {
 		return false, err
 	}
@@ -93,22 +112,34 @@ func (s *systemdDriver) Install(override bool) (bool, error) {
 func (s *systemdDriver) Uninstall() error {
 	if s.Exists() {
 		// Stop service
-		err := cmdError(exec.Command("sudo", "systemctl", "stop", s.name).Run())
+		err := runLive(s.runner, "systemctl", "stop", s.name)
 		if err != nil {
 			return err
 		}
 
 		// Disable service
-		err = cmdError(exec.Command("sudo", "systemctl", "disable", s.name).Run())
+		if staging() {
+			err = removeFile(s.runner, s.link())
+		} else {
+			_, err = runRoot(s.runner, "", "systemctl", "disable", s.name)
+		}
 		if err != nil {
 			return err
 		}
 	}
 
-	err := os.Remove(s.path())
-	if err != nil && !os.IsNotExist(err) {
+	return removeFile(s.runner, s.path())
+}
+
+// wantsLink get enable link of unit in wants directory of target.
+func wantsLink(target, unit string) string {
+	return "/etc/systemd/system/" + target + ".wants/" + unit
+}
+
+// enableLink creates enable link of unit if not exists.
+func enableLink(runner Runner, unit, link string) error {
+	if exists, err := linkExists(runner, link); err != nil || exists {
 		return err
 	}
-
-	return nil
+	return symlink(runner, unit, link)
 }
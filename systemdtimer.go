@@ -0,0 +1,45 @@
+package gounix
+
+import "time"
+
+// SystemdTimer systemd timer manager. runs cron job schedule
+// as paired <name>.timer and <name>.service units.
+type SystemdTimer interface {
+	// Persistent catches up runs missed while machine was off (default true).
+	Persistent(persistent bool) SystemdTimer
+	// RandomizedDelay delays each run by random time up to d.
+	RandomizedDelay(d time.Duration) SystemdTimer
+	// Accuracy sets timer accuracy (systemd default 1 minute).
+	Accuracy(d time.Duration) SystemdTimer
+	// Runner sets command runner of the timer (default runner if nil).
+	Runner(runner Runner) SystemdTimer
+	// OnCalendar returns OnCalendar expressions of cron job schedule.
+	// day of month and day of week restricted together compiled
+	// as two expressions (cron matches either one of them).
+	OnCalendar() ([]string, error)
+	// Timer returns content of timer unit.
+	Timer() (string, error)
+	// Service returns content of service unit.
+	Service() string
+	// Exists checks if the timer unit exists.
+	Exists() bool
+	// Enabled checks if the timer exists and enabled on startup.
+	Enabled() bool
+	// Install installs, enables and starts the timer.
+	// override parameter indicating whether to override existing units.
+	// returns false if timer exists and not override.
+	Install(override bool) (bool, error)
+	// Uninstall stops, disables and removes the timer and service units.
+	Uninstall() error
+}
+
+// NewSystemdTimer creates systemd timer of cron job. location based
+// timezones written as OnCalendar timezone suffix, fixed offsets
+// translated to system local time. @reboot jobs run on boot.
+func NewSystemdTimer(name string, job CronJob) SystemdTimer {
+	timer := new(systemdTimerDriver)
+	timer.name = name
+	timer.job = job
+	timer.persistent = true
+	return timer
+}
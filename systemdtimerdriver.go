@@ -0,0 +1,299 @@
+package gounix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calendarWeekdays systemd weekday names.
+var calendarWeekdays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+type systemdTimerDriver struct {
+	name       string
+	job        CronJob
+	persistent bool
+	delay      time.Duration
+	accuracy   time.Duration
+	runner     Runner
+}
+
+func (s systemdTimerDriver) path(unit string) string {
+	return "/etc/systemd/system/" + s.name + "." + unit
+}
+
+// driver get cron driver of job.
+func (s systemdTimerDriver) driver() (*cronDriver, error) {
+	if driver, ok := s.job.(*cronDriver); ok && driver != nil {
+		return driver, nil
+	}
+	return nil, errors.New("unsupported cron job implementation")
+}
+
+// seconds formats duration as systemd seconds value.
+func (s systemdTimerDriver) seconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10) + "s"
+}
+
+func (s *systemdTimerDriver) Persistent(persistent bool) SystemdTimer {
+	s.persistent = persistent
+	return s
+}
+
+func (s *systemdTimerDriver) RandomizedDelay(d time.Duration) SystemdTimer {
+	s.delay = d
+	return s
+}
+
+func (s *systemdTimerDriver) Accuracy(d time.Duration) SystemdTimer {
+	s.accuracy = d
+	return s
+}
+
+func (s *systemdTimerDriver) OnCalendar() ([]string, error) {
+	driver, err := s.driver()
+	if err != nil {
+		return nil, err
+	} else if driver.reboot {
+		return []string{}, nil
+	}
+
+	// Location timezones evaluated by systemd, fixed offsets translated
+	var fields [5]string
+	suffix := ""
+	if driver.tz != nil && driver.tz.location != nil {
+		fields = [5]string{driver.minute, driver.hour, driver.day, driver.month, driver.weekday}
+		if shifted, ok := shiftCron(fields, driver.splayOffset()); ok {
+			fields = shifted
+		}
+		suffix = " " + driver.tz.location.String()
+	} else {
+		interval, err := driver.interval()
+		if err != nil {
+			return nil, err
+		}
+		fields = [5]string(strings.Fields(interval))
+	}
+	return calendarExprs(fields, suffix)
+}
+
+func (s *systemdTimerDriver) Timer() (string, error) {
+	driver, err := s.driver()
+	if err != nil {
+		return "", err
+	}
+	calendars, err := s.OnCalendar()
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	result.WriteString("[Unit]\nDescription=" + s.name + " timer\n\n[Timer]\n")
+	if driver.reboot {
+		result.WriteString("OnBootSec=0\n")
+	}
+	for _, calendar := range calendars {
+		result.WriteString("OnCalendar=" + calendar + "\n")
+	}
+	if s.persistent && !driver.reboot {
+		result.WriteString("Persistent=true\n")
+	}
+	if s.delay > 0 {
+		result.WriteString("RandomizedDelaySec=" + s.seconds(s.delay) + "\n")
+	}
+	if s.accuracy > 0 {
+		result.WriteString("AccuracySec=" + s.seconds(s.accuracy) + "\n")
+	}
+	result.WriteString("Unit=" + s.name + ".service\n\n[Install]\nWantedBy=timers.target\n")
+	return result.String(), nil
+}
+
+func (s *systemdTimerDriver) Service() string {
+	user, command := "root", ""
+	if driver, err := s.driver(); err == nil {
+		command = driver.wrapped()
+		if driver.user != "" {
+			user = driver.user
+		}
+	}
+	return "[Unit]\nDescription=" + s.name + "\n\n" +
+		"[Service]\nType=oneshot\nUser=" + user + "\n" +
+		"ExecStart=/bin/sh -c " + systemdQuote(command) + "\n"
+}
+
+func (s *systemdTimerDriver) Runner(runner Runner) SystemdTimer {
+	s.runner = runner
+	return s
+}
+
+func (s *systemdTimerDriver) Exists() bool {
+	exists, _ := fileExists(s.runner, s.path("timer"))
+	return exists
+}
+
+func (s *systemdTimerDriver) Enabled() bool {
+	if staging() {
+		enabled, _ := linkExists(s.runner, wantsLink("timers.target", s.name+".timer"))
+		return enabled
+	}
+	result, _ := runRoot(s.runner, "", "systemctl", "is-enabled", s.name+".timer")
+	return strings.HasPrefix(result.Stdout, "enabled")
+}
+
+func (s *systemdTimerDriver) Install(override bool) (bool, error) {
+	// Validate name and cron job
+	if s.name == "" || strings.ContainsAny(s.name, "/ \t\n") {
+		return false, fmt.Errorf("invalid systemd timer name %q", s.name)
+	}
+	driver, err := s.driver()
+	if err != nil {
+		return false, err
+	}
+	if err := driver.validate(driver.tz == nil || driver.tz.location == nil); err != nil {
+		return false, err
+	}
+
+	// Check exists and override
+	exists := s.Exists()
+	if exists && !override {
+		return false, nil
+	}
+
+	// Create unit files
+	timer, err := s.Timer()
+	if err != nil {
+		return false, err
+	}
+	err = writeFile(s.runner, s.path("service"), []byte(s.Service()), 0644)
+	if err != nil {
+		return false, err
+	}
+	err = writeFile(s.runner, s.path("timer"), []byte(timer), 0644)
+	if err != nil {
+		return false, err
+	}
+
+	// Reload units
+	err = runLive(s.runner, "systemctl", "daemon-reload")
+	if err != nil {
+		return false, err
+	}
+
+	// Enable and start timer
+	if staging() {
+		err = enableLink(s.runner, s.path("timer"), wantsLink("timers.target", s.name+".timer"))
+	} else {
+		_, err = runRoot(s.runner, "", "systemctl", "enable", "--now", s.name+".timer")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *systemdTimerDriver) Uninstall() error {
+	if s.Exists() {
+		// Stop and disable timer
+		var err error
+		if staging() {
+			err = removeFile(s.runner, wantsLink("timers.target", s.name+".timer"))
+		} else {
+			_, err = runRoot(s.runner, "", "systemctl", "disable", "--now", s.name+".timer")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove unit files
+	for _, unit := range []string{"timer", "service"} {
+		err := removeFile(s.runner, s.path(unit))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Reload units
+	return runLive(s.runner, "systemctl", "daemon-reload")
+}
+
+// calendarExprs converts five cron fields into systemd OnCalendar expressions.
+func calendarExprs(fields [5]string, suffix string) ([]string, error) {
+	schedule, err := newCronSchedule(strings.Join(fields[:], " "))
+	if err != nil {
+		return nil, err
+	}
+
+	number := func(v int) string { return fmt.Sprintf("%02d", v) }
+	weekday := func(v int) string { return calendarWeekdays[v] }
+	clock := calendarField(hourField, schedule.hour, number, true) + ":" +
+		calendarField(minuteField, schedule.minute, number, true) + ":00"
+	month := calendarField(monthField, schedule.month, number, true)
+	day := calendarField(dayField, schedule.day, number, true)
+	weekdays := ""
+	if !weekdayField.full(schedule.weekday) {
+		weekdays = calendarField(weekdayField, schedule.weekday, weekday, false) + " "
+	}
+
+	// Day of month and day of week restricted together matches either one
+	if weekdays != "" && !dayField.full(schedule.day) && !schedule.dayStar && !schedule.weekdayStar {
+		return []string{
+			weekdays + "*-" + month + "-* " + clock + suffix,
+			"*-" + month + "-" + day + " " + clock + suffix,
+		}, nil
+	}
+	return []string{weekdays + "*-" + month + "-" + day + " " + clock + suffix}, nil
+}
+
+// calendarField formats values set into systemd calendar component
+// (e.g. 00/15, 09..17, Mon..Fri).
+func calendarField(f cronField, set cronSet, name func(int) string, step bool) string {
+	if f.full(set) {
+		return "*"
+	}
+	max := f.max
+	if f.wrap {
+		max--
+	}
+
+	// Collect values
+	values := make([]int, 0)
+	for v := f.min; v <= max; v++ {
+		if set.has(v) {
+			values = append(values, v)
+		}
+	}
+
+	// Detect repetition to end of field (from/step)
+	if step && len(values) > 2 && values[1]-values[0] > 1 {
+		n, stepped := values[1]-values[0], true
+		for i := 1; stepped && i < len(values); i++ {
+			stepped = values[i]-values[i-1] == n
+		}
+		if stepped && values[len(values)-1]+n > max {
+			return name(values[0]) + "/" + strconv.Itoa(n)
+		}
+	}
+
+	// Join runs as ranges
+	parts := make([]string, 0)
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+		switch j - i {
+		case 0:
+			parts = append(parts, name(values[i]))
+		case 1:
+			parts = append(parts, name(values[i]), name(values[j]))
+		default:
+			parts = append(parts, name(values[i])+".."+name(values[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
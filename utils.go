@@ -3,24 +3,32 @@ package gounix
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 )
 
-// cmdError handle execute error with exit code.
-func cmdError(err error) error {
-	if err == nil {
-		return nil
-	} else if exitErr, ok := err.(*exec.ExitError); ok {
-		return fmt.Errorf("Exit %d, %s", exitErr.ExitCode(), string(exitErr.Stderr))
+// fileExists check if file exists.
+func fileExists(runner Runner, filePath string) (bool, error) {
+	if !direct(runner) {
+		return testFile(runner, "-e", filePath)
+	}
+	if _, err := fileSystem.Stat(filePath); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
 	} else {
-		return err
+		return true, nil
 	}
 }
 
-// fileExists check if file exists.
-func fileExists(filePath string) (bool, error) {
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+// linkExists check if file or symbolic link exists.
+func linkExists(runner Runner, filePath string) (bool, error) {
+	if !direct(runner) {
+		return testFile(runner, "-L", filePath, "-o", "-e", filePath)
+	}
+	if _, err := fileSystem.Lstat(filePath); os.IsNotExist(err) {
 		return false, nil
 	} else if err != nil {
 		return false, err
@@ -29,34 +37,198 @@ func fileExists(filePath string) (bool, error) {
 	}
 }
 
-// parseCommand extracts the command from a cron expression.
-func parseCommand(cronExpr string) (bool, string) {
-	// Handle predefined constants
-	aliases := []string{
-		"@reboot ", "@yearly ", "@annually ",
-		"@monthly ", "@weekly ", "@daily ",
-		"@midnight ", "@hourly ",
+// testFile checks file condition with escalated test command.
+func testFile(runner Runner, args ...string) (bool, error) {
+	result, err := runRoot(runner, "", append([]string{"test"}, args...)...)
+	if err != nil && result.ExitCode == 1 && strings.TrimSpace(result.Stderr) == "" {
+		return false, nil
 	}
-	for _, alias := range aliases {
-		if strings.HasPrefix(cronExpr, alias) {
-			return true, strings.TrimPrefix(cronExpr, alias)
+	return err == nil, err
+}
+
+// crontabLockTimeout maximum wait of crontab lock.
+const crontabLockTimeout = 30 * time.Second
+
+// stagingLock crontab lock of staging filesystems and remote runners.
+var stagingLock sync.Mutex
+
+// crontabLock get lock file of crontab read-modify-write cycles derived
+// from target store, so root and escalated processes writing the same
+// crontab share the lock. user cache directory used if /run/lock missing.
+func crontabLock(store CronStore) (string, error) {
+	key := "crontab"
+	switch s := store.(type) {
+	case *userCrontab:
+		key = "crontab-root"
+		if s.user != "" {
+			key = "crontab-" + s.user
 		}
+	case *cronDFile:
+		key = "crond-" + s.name
+	}
+	if info, err := os.Stat("/run/lock"); err == nil && info.IsDir() {
+		return "/run/lock/gounix-" + key + ".lock", nil
 	}
 
-	// Handle custom cron expressions
-	parts := strings.Fields(cronExpr)
-	if len(parts) < 6 {
-		return false, ""
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
 	}
-	return true, strings.Join(parts[5:], " ")
+	return dir + "/gounix-" + key + ".lock", nil
 }
 
-// allCrons get all system cron jobs.
-func allCrons() ([]string, error) {
-	out, err := exec.Command("sudo", "crontab", "-l").Output()
-	err = cmdError(err)
+// lockCrontab acquires exclusive lock of store between processes (in process
+// on staging filesystems and remote runners). lock file of other users opened
+// read only, symlinked or lock file not owned by root or current user refused.
+// returns unlock function.
+func lockCrontab(store CronStore, runner Runner) (func(), error) {
+	if _, local := runnerOf(runner).(*localRunner); staging() || !local {
+		stagingLock.Lock()
+		return stagingLock.Unlock, nil
+	}
+
+	path, err := crontabLock(store)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|syscall.O_NOFOLLOW, 0644)
+	if os.IsPermission(err) {
+		file, err = os.OpenFile(path, os.O_RDONLY|syscall.O_NOFOLLOW, 0)
+	}
 	if err != nil {
 		return nil, err
 	}
-	return strings.Split(string(out), "\n"), nil
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(int(file.Fd()), &stat); err != nil {
+		file.Close()
+		return nil, err
+	} else if stat.Uid != 0 && int(stat.Uid) != os.Geteuid() {
+		file.Close()
+		return nil, fmt.Errorf("crontab lock %s not owned by root or current user", path)
+	}
+
+	// Retry until deadline
+	deadline := time.Now().Add(crontabLockTimeout)
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		} else if err != syscall.EWOULDBLOCK || time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("crontab lock %s, %w", path, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
+
+// escapeCommand escapes cron special % character of command.
+// unescaped % converted to new line by cron daemon.
+func escapeCommand(command string) string {
+	return strings.ReplaceAll(command, "%", `\%`)
+}
+
+// unescapeCommand reverts escaped % characters of crontab command.
+func unescapeCommand(command string) string {
+	return strings.ReplaceAll(command, `\%`, "%")
+}
+
+// shellQuote quotes string as single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// systemdQuote quotes string as single systemd unit command argument.
+// specifiers (%) and variables ($) escaped.
+func systemdQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(s)
+	return `"` + s + `"`
+}
+
+// parseEnv parses crontab environment assignment line.
+func parseEnv(text string) (string, string, bool) {
+	key, value, ok := strings.Cut(text, "=")
+	key = strings.TrimSpace(key)
+	if !ok || !isEnvName(key) {
+		return "", "", false
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	return key, value, true
+}
+
+// quoteEnv quotes environment value if needed.
+func quoteEnv(value string) string {
+	if value != strings.TrimSpace(value) ||
+		strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// daemonZone get IANA name of system timezone used by cron daemon
+// from /etc/timezone or /etc/localtime link.
+func daemonZone() (string, bool) {
+	if content, err := fileSystem.ReadFile("/etc/timezone"); err == nil {
+		if zone := strings.TrimSpace(string(content)); zone != "" {
+			return zone, true
+		}
+	}
+	if !staging() {
+		if target, err := os.Readlink("/etc/localtime"); err == nil {
+			if _, zone, ok := strings.Cut(target, "zoneinfo/"); ok && zone != "" {
+				return zone, true
+			}
+		}
+	}
+	return "", false
+}
+
+// setEnvs sets or appends environment value of key in list.
+func setEnvs(envs [][2]string, key, value string) [][2]string {
+	for i, env := range envs {
+		if env[0] == key {
+			envs[i][1] = value
+			return envs
+		}
+	}
+	return append(envs, [2]string{key, value})
+}
+
+// isEnvName checks if name is valid environment variable name.
+func isEnvName(name string) bool {
+	for i, r := range name {
+		if r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// markerPrefix prefix of managed cron job marker comment.
+const markerPrefix = "# gounix:"
+
+// parseMarker parses managed marker comment attributes (e.g. # gounix:id=backup).
+func parseMarker(text string) (map[string]string, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, markerPrefix) {
+		return nil, false
+	}
+
+	result := make(map[string]string)
+	for _, field := range strings.Fields(strings.TrimPrefix(text, markerPrefix)) {
+		if key, value, ok := strings.Cut(field, "="); ok {
+			result[key] = value
+		}
+	}
+	return result, result["id"] != "" || result["env"] != ""
 }